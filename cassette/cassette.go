@@ -0,0 +1,274 @@
+// Package cassette provides an http.RoundTripper that records the requests
+// sent by the encodingcom and elementalconductor clients, along with their
+// responses, to fixture files, and replays them later without touching the
+// network.
+//
+// Credentials are redacted before interactions are stored or matched: the
+// userkey sent in Encoding.com queries, the X-Auth-* headers sent to
+// Elemental Conductor (which carry the user login and the key derived from
+// the API key), the username and password of locations in Conductor jobs
+// (usually AWS credentials) and the credentials embedded in URLs (e.g. the
+// source and destination of Encoding.com media, like
+// s3://key:secret@bucket/file.mp4), in both requests and responses, never
+// reach the fixture files. Headers that may carry session credentials
+// (Authorization, Cookie and Set-Cookie) are redacted too.
+package cassette // import "github.com/NYTimes/encoding-wrapper/cassette"
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+)
+
+// Redacted is the value that replaces credentials in recorded interactions.
+const Redacted = "REDACTED"
+
+// Mode defines whether a Recorder records new interactions or replays
+// previously recorded ones.
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette file, failing requests
+	// that don't match any of the recorded interactions.
+	ModeReplay = Mode(iota)
+
+	// ModeRecord sends requests to the real server and stores the
+	// interactions, writing them to the cassette file on Stop.
+	ModeRecord
+)
+
+var redactedHeaders = []string{"X-Auth-User", "X-Auth-Key", "X-Auth-Expires"}
+
+// sessionHeaders are redacted when present, in both requests and responses.
+var sessionHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+var bodyRedactions = []*regexp.Regexp{
+	regexp.MustCompile(`("userkey"\s*:\s*")[^"]*(")`),
+	regexp.MustCompile(`(<userkey>)[^<]*(</userkey>)`),
+	regexp.MustCompile(`(<username>)[^<]*(</username>)`),
+	regexp.MustCompile(`(<password>)[^<]*(</password>)`),
+	// userinfo in URLs, also matching slashes escaped in JSON (s3:\/\/).
+	regexp.MustCompile(`([a-zA-Z][a-zA-Z0-9+.-]*:(?:\\?/){2})[^/@\s"'<>]+(@)`),
+}
+
+// Cassette is the list of interactions stored in a fixture file.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request/response pair.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is the recorded version of an http.Request.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is the recorded version of an http.Response.
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records or replays interactions,
+// depending on its mode.
+//
+// Transport is the http.RoundTripper used for sending requests in
+// ModeRecord, http.DefaultTransport is used when it's nil.
+type Recorder struct {
+	Transport http.RoundTripper
+
+	path     string
+	mode     Mode
+	mtx      sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+// New creates a Recorder for the given cassette file. In ModeReplay, the file
+// is loaded immediately and must exist.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode}
+	if mode == ModeReplay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(data, &r.cassette)
+		if err != nil {
+			return nil, err
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Client returns an http.Client that uses the recorder as its transport. It
+// can be assigned to the HTTPClient field of the encodingcom and
+// elementalconductor clients.
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Interactions returns the interactions recorded or loaded by the recorder.
+func (r *Recorder) Interactions() []Interaction {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	interactions := make([]Interaction, len(r.cassette.Interactions))
+	copy(interactions, r.cassette.Interactions)
+	return interactions
+}
+
+// Stop writes the recorded interactions to the cassette file. It's a no-op in
+// ModeReplay.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	data, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(data, '\n'), 0644)
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, body, err := newRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeRecord {
+		return r.record(req, recorded, body)
+	}
+	return r.replay(req, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, recorded Request, body []byte) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	outReq := req.WithContext(req.Context())
+	outReq.Body = ioutil.NopCloser(bytes.NewReader(body))
+	resp, err := transport.RoundTrip(outReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.mtx.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+			Body:       redact(string(respBody)),
+		},
+	})
+	r.mtx.Unlock()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !interaction.Request.matches(recorded) {
+			continue
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header,
+			Body:          ioutil.NopCloser(bytes.NewReader([]byte(interaction.Response.Body))),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("cassette: no recorded interaction for %s %s", recorded.Method, recorded.URL)
+}
+
+func (r Request) matches(other Request) bool {
+	return r.Method == other.Method && r.URL == other.URL && r.Body == other.Body
+}
+
+// newRequest builds the redacted version of the given request, returning it
+// along with the original body, so it can still be sent to the server.
+func newRequest(req *http.Request) (Request, []byte, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		if err != nil {
+			return Request{}, nil, err
+		}
+		req.Body.Close()
+	}
+	return Request{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: redactHeader(req.Header),
+		Body:   redactBody(req.Header.Get("Content-Type"), body),
+	}, body, nil
+}
+
+// redactHeader returns a copy of the given header, with the values of
+// redactedHeaders and sessionHeaders replaced by Redacted.
+func redactHeader(h http.Header) http.Header {
+	header := make(http.Header, len(h))
+	for key, values := range h {
+		header[key] = append([]string(nil), values...)
+	}
+	for _, key := range append(redactedHeaders, sessionHeaders...) {
+		if header.Get(key) != "" {
+			header.Set(key, Redacted)
+		}
+	}
+	return header
+}
+
+func redactBody(contentType string, body []byte) string {
+	if contentType != "application/x-www-form-urlencoded" {
+		return redact(string(body))
+	}
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return redact(string(body))
+	}
+	for key := range values {
+		for i, value := range values[key] {
+			values[key][i] = redact(value)
+		}
+	}
+	return values.Encode()
+}
+
+func redact(value string) string {
+	for _, re := range bodyRedactions {
+		value = re.ReplaceAllString(value, "${1}"+Redacted+"${2}")
+	}
+	return value
+}
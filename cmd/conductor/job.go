@@ -0,0 +1,215 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/NYTimes/encoding-wrapper/elementalconductor"
+	"github.com/NYTimes/encoding-wrapper/internal/yamlconv"
+)
+
+const progressBarWidth = 40
+
+// jobYAML decodes YAML job definitions, using the names in the XML
+// representation of jobs (e.g. "stream_assembly") as keys.
+var jobYAML = yamlconv.Decoder{Tag: "xml", True: "true", False: "false"}
+
+func jobs(c *cli, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	switch args[0] {
+	case "list":
+		return listJobs(c, args[1:])
+	case "get":
+		return getJob(c, args[1:])
+	case "create":
+		return createJob(c, args[1:])
+	case "cancel":
+		return cancelJob(c, args[1:])
+	}
+	return errUsage
+}
+
+func listJobs(c *cli, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	list, err := c.client.GetJobs()
+	if err != nil {
+		return err
+	}
+	return c.print(list.Job, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tSTATUS\tPROGRESS\tSUBMITTED")
+		for _, job := range list.Job {
+			fmt.Fprintf(w, "%s\t%s\t%d%%\t%s\n", job.GetID(), job.Status, job.PercentComplete, formatTime(job.Submitted.Time))
+		}
+	})
+}
+
+func getJob(c *cli, args []string) error {
+	flags := c.flagSet("jobs get")
+	watch, interval := watchFlags(flags)
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+	if *watch {
+		return c.watchJob(flags.Arg(0), *interval)
+	}
+	job, err := c.client.GetJob(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	return c.printJob(job)
+}
+
+func createJob(c *cli, args []string) error {
+	flags := c.flagSet("jobs create")
+	watch, interval := watchFlags(flags)
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+	job, err := loadJob(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	job, err = c.client.CreateJob(job)
+	if err != nil {
+		return err
+	}
+	if *watch {
+		if !c.json {
+			fmt.Fprintf(c.stdout, "created job %s\n", job.GetID())
+		}
+		return c.watchJob(job.GetID(), *interval)
+	}
+	return c.printJob(job)
+}
+
+func cancelJob(c *cli, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	job, err := c.client.CancelJob(args[0])
+	if err != nil {
+		return err
+	}
+	return c.printJob(job)
+}
+
+func watchFlags(flags *flag.FlagSet) (*bool, *time.Duration) {
+	watch := flags.Bool("watch", false, "display the progress of the job until it finishes")
+	interval := flags.Duration("interval", 5*time.Second, "polling interval used with -watch")
+	return watch, interval
+}
+
+// loadJob reads a job definition from the given file. Files with the .yaml
+// or .yml extensions are decoded as YAML, files with the .json extension are
+// decoded as JSON, and everything else is decoded as XML.
+func loadJob(path string) (*elementalconductor.Job, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var job elementalconductor.Job
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = jobYAML.Unmarshal(data, &job)
+	case ".json":
+		err = json.Unmarshal(data, &job)
+	default:
+		err = xml.Unmarshal(data, &job)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid job file %s: %s", path, err)
+	}
+	return &job, nil
+}
+
+// watchJob polls the given job, rendering a progress bar, until it reaches a
+// terminal status. In JSON mode, the progress bar is omitted and the final
+// state of the job is printed instead. Jobs that end in error or are
+// cancelled make the command fail, as their outputs weren't generated.
+func (c *cli) watchJob(jobID string, interval time.Duration) error {
+	for {
+		job, err := c.client.GetJob(jobID)
+		if err != nil {
+			return err
+		}
+		if !c.json {
+			fmt.Fprintf(c.stdout, "\r%s", progressBar(job.PercentComplete, job.Status))
+		}
+		if job.Status.IsTerminal() {
+			if c.json {
+				err = c.printJob(job)
+			} else {
+				fmt.Fprintln(c.stdout)
+			}
+			if err != nil {
+				return err
+			}
+			switch {
+			case job.Status.Is(elementalconductor.JobStatusError):
+				return jobError(job)
+			case job.Status.Is(elementalconductor.JobStatusCancelled):
+				return fmt.Errorf("job %s was cancelled", job.GetID())
+			}
+			return nil
+		}
+		c.sleep(interval)
+	}
+}
+
+func progressBar(pct int, status elementalconductor.JobStatus) string {
+	if pct < 0 {
+		pct = 0
+	} else if pct > 100 {
+		pct = 100
+	}
+	filled := pct * progressBarWidth / 100
+	return fmt.Sprintf("[%s%s] %3d%% %-14s", strings.Repeat("#", filled), strings.Repeat("-", progressBarWidth-filled), pct, status)
+}
+
+func jobError(job *elementalconductor.Job) error {
+	messages := make([]string, len(job.ErrorMessages))
+	for i, jobErr := range job.ErrorMessages {
+		messages[i] = jobErr.Message
+	}
+	return fmt.Errorf("job %s failed: %s", job.GetID(), strings.Join(messages, "; "))
+}
+
+func (c *cli) printJob(job *elementalconductor.Job) error {
+	return c.print(job, func(w io.Writer) {
+		fmt.Fprintf(w, "ID:\t%s\n", job.GetID())
+		fmt.Fprintf(w, "Status:\t%s\n", job.Status)
+		fmt.Fprintf(w, "Progress:\t%d%%\n", job.PercentComplete)
+		fmt.Fprintf(w, "Submitted:\t%s\n", formatTime(job.Submitted.Time))
+		fmt.Fprintf(w, "Started:\t%s\n", formatTime(job.StartTime.Time))
+		fmt.Fprintf(w, "Completed:\t%s\n", formatTime(job.CompleteTime.Time))
+		for _, input := range job.Input {
+			fmt.Fprintf(w, "Input:\t%s\n", input.FileInput.URI)
+		}
+		for _, group := range job.OutputGroup {
+			for _, output := range group.Output {
+				fmt.Fprintf(w, "Output:\t%s\n", output.FullURI)
+			}
+		}
+		for _, jobErr := range job.ErrorMessages {
+			fmt.Fprintf(w, "Error:\t%s\n", jobErr.Message)
+		}
+	})
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,160 @@
+// Command conductor is a command line tool for interacting with the
+// Elemental Conductor API, built on top of the elementalconductor package.
+//
+// Credentials and settings are read from the environment:
+//
+//	CONDUCTOR_HOST          the Conductor host, including the scheme (required)
+//	CONDUCTOR_USER_LOGIN    the login of the API user (required)
+//	CONDUCTOR_API_KEY       the API key of the user (required)
+//	CONDUCTOR_AUTH_EXPIRES  lifetime of the authentication key, in seconds (default: 45)
+//	CONDUCTOR_DESTINATION   default destination of job outputs
+//	AWS_ACCESS_KEY_ID       access key used in S3 inputs and destinations
+//	AWS_SECRET_ACCESS_KEY   secret key used in S3 inputs and destinations
+//
+// Job definitions given to "jobs create" may be written in XML, in the format
+// expected by the Conductor API, or in YAML (.yaml or .yml files) or JSON
+// (.json files), using the field names of the elementalconductor.Job type. In
+// YAML, keys are the names used in the XML representation (e.g.
+// stream_assembly or caption_description_name), though the field names are
+// accepted too, and unknown keys are rejected. Preset exports, imports and
+// the definitions used by "presets sync" always use XML.
+//
+// Run "conductor help" for the list of available commands.
+package main // import "github.com/NYTimes/encoding-wrapper/cmd/conductor"
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NYTimes/encoding-wrapper/elementalconductor"
+)
+
+const defaultAuthExpires = 45
+
+var errUsage = errors.New("invalid usage")
+
+type command struct {
+	usage       string
+	description string
+	run         func(c *cli, args []string) error
+}
+
+var commands = map[string]command{
+	"jobs":         {"jobs list | get [-watch] [-interval <duration>] <id> | create [-watch] [-interval <duration>] <file> | cancel <id>", "manage jobs", jobs},
+	"presets":      {"presets list | get <id> | export [-o <file>] <id> | import <file> | sync [-apply] <dir>", "manage presets", presets},
+	"nodes":        {"nodes list", "list the nodes in the cluster", nodes},
+	"cloud-config": {"cloud-config get", "show the cloud configuration", cloudConfig},
+}
+
+type cli struct {
+	client *elementalconductor.Client
+	stdout io.Writer
+	stderr io.Writer
+	json   bool
+	sleep  func(time.Duration)
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Getenv, os.Stdout, os.Stderr))
+}
+
+func run(args []string, getenv func(string) string, stdout, stderr io.Writer) int {
+	c := cli{stdout: stdout, stderr: stderr, sleep: time.Sleep}
+	return c.run(args, getenv)
+}
+
+func (c *cli) run(args []string, getenv func(string) string) int {
+	flags := flag.NewFlagSet("conductor", flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.BoolVar(&c.json, "json", false, "print output in JSON format")
+	flags.Usage = func() { c.usage() }
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 || flags.Arg(0) == "help" {
+		c.usage()
+		return 2
+	}
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(c.stderr, "unknown command %q\n", flags.Arg(0))
+		c.usage()
+		return 2
+	}
+	client, err := newClient(getenv)
+	if err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return 2
+	}
+	c.client = client
+	err = cmd.run(c, flags.Args()[1:])
+	if err == errUsage {
+		fmt.Fprintf(c.stderr, "usage: conductor %s\n", cmd.usage)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func newClient(getenv func(string) string) (*elementalconductor.Client, error) {
+	host, userLogin, apiKey := getenv("CONDUCTOR_HOST"), getenv("CONDUCTOR_USER_LOGIN"), getenv("CONDUCTOR_API_KEY")
+	if host == "" || userLogin == "" || apiKey == "" {
+		return nil, errors.New("CONDUCTOR_HOST, CONDUCTOR_USER_LOGIN and CONDUCTOR_API_KEY must be set")
+	}
+	authExpires := defaultAuthExpires
+	if value := getenv("CONDUCTOR_AUTH_EXPIRES"); value != "" {
+		var err error
+		authExpires, err = strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CONDUCTOR_AUTH_EXPIRES %q: %s", value, err)
+		}
+	}
+	return elementalconductor.NewClient(host, userLogin, apiKey, authExpires, getenv("AWS_ACCESS_KEY_ID"), getenv("AWS_SECRET_ACCESS_KEY"), getenv("CONDUCTOR_DESTINATION")), nil
+}
+
+func (c *cli) usage() {
+	fmt.Fprintln(c.stderr, "usage: conductor [-json] <command> [arguments]\n\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w := tabwriter.NewWriter(c.stderr, 0, 8, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\t%s\n", name, commands[name].description)
+	}
+	w.Flush()
+}
+
+func (c *cli) flagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	return flags
+}
+
+// print writes the given value to the standard output, either in JSON format
+// or as a table, using the given function for writing the rows.
+func (c *cli) print(v interface{}, table func(w io.Writer)) error {
+	if c.json {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(c.stdout, "%s\n", data)
+		return err
+	}
+	w := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+	table(w)
+	return w.Flush()
+}
@@ -0,0 +1,445 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NYTimes/encoding-wrapper/elementalconductor"
+	"github.com/NYTimes/encoding-wrapper/elementalconductor/elementalconductortest"
+)
+
+type testCLI struct {
+	cli
+	env    map[string]string
+	stdout bytes.Buffer
+	stderr bytes.Buffer
+}
+
+func newTestCLI(server *elementalconductortest.Server) *testCLI {
+	c := testCLI{env: map[string]string{
+		"CONDUCTOR_HOST":       server.URL,
+		"CONDUCTOR_USER_LOGIN": "myuser",
+		"CONDUCTOR_API_KEY":    "secret-key",
+	}}
+	c.cli.stdout = &c.stdout
+	c.cli.stderr = &c.stderr
+	c.cli.sleep = server.Advance
+	return &c
+}
+
+func (c *testCLI) run(args ...string) int {
+	c.stdout.Reset()
+	c.stderr.Reset()
+	c.cli.json = false
+	return c.cli.run(args, func(key string) string { return c.env[key] })
+}
+
+func tempDir(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "conductor")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestCreateJobAndWatch(t *testing.T) {
+	server := elementalconductortest.NewServer("myuser", "secret-key")
+	defer server.Close()
+	server.SetProcessingTime(100 * time.Second)
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	jobFile := filepath.Join(dir, "job.xml")
+	ioutil.WriteFile(jobFile, []byte(`<job>
+  <input><file_input><uri>s3://mybucket/source.mov</uri></file_input></input>
+  <stream_assembly><name>stream_1</name><preset>mp4_1080p</preset></stream_assembly>
+</job>`), 0644)
+	c := newTestCLI(server)
+
+	if code := c.run("jobs", "create", "-watch", "-interval", "25s", jobFile); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	output := c.stdout.String()
+	if !strings.HasPrefix(output, "created job 1\n") {
+		t.Errorf("wrong output:\n%s", output)
+	}
+	if n := strings.Count(output, "\r"); n != 5 {
+		t.Errorf("wrong number of polls\nwant 5\ngot  %d\n%q", n, output)
+	}
+	if !strings.HasSuffix(output, progressBar(100, elementalconductor.JobStatusComplete)+"\n") {
+		t.Errorf("wrong final progress bar:\n%q", output)
+	}
+
+	if code := c.run("-json", "jobs", "list"); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	var jobs []elementalconductor.Job
+	if err := json.Unmarshal(c.stdout.Bytes(), &jobs); err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].Status != elementalconductor.JobStatusComplete {
+		t.Errorf("wrong jobs: %#v", jobs)
+	}
+}
+
+func TestWatchFailedJob(t *testing.T) {
+	server := elementalconductortest.NewServer("myuser", "secret-key")
+	defer server.Close()
+	client := server.Client()
+	job, err := client.CreateJob(&elementalconductor.Job{
+		Input: []elementalconductor.Input{{FileInput: elementalconductor.Location{URI: "s3://mybucket/source.mov"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.FailJob(job.GetID(), "failed to download the input")
+	c := newTestCLI(server)
+	if code := c.run("jobs", "get", "-watch", job.GetID()); code != 1 {
+		t.Errorf("wrong exit code\nwant 1\ngot  %d", code)
+	}
+	expectedErr := "job 1 failed: failed to download the input\n"
+	if c.stderr.String() != expectedErr {
+		t.Errorf("wrong error\nwant %q\ngot  %q", expectedErr, c.stderr.String())
+	}
+}
+
+func TestWatchJobJSON(t *testing.T) {
+	server := elementalconductortest.NewServer("myuser", "secret-key")
+	defer server.Close()
+	server.SetProcessingTime(100 * time.Second)
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	jobFile := filepath.Join(dir, "job.json")
+	ioutil.WriteFile(jobFile, []byte(`{"Input": [{"FileInput": {"URI": "s3://mybucket/source.mov"}}]}`), 0644)
+	c := newTestCLI(server)
+	if code := c.run("-json", "jobs", "create", "-watch", "-interval", "25s", jobFile); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	var job elementalconductor.Job
+	if err := json.Unmarshal(c.stdout.Bytes(), &job); err != nil {
+		t.Fatalf("invalid JSON output: %s\n%q", err, c.stdout.String())
+	}
+	if job.Status != elementalconductor.JobStatusComplete || job.PercentComplete != 100 {
+		t.Errorf("wrong job: %#v", job)
+	}
+}
+
+func TestWatchCancelledJob(t *testing.T) {
+	server := elementalconductortest.NewServer("myuser", "secret-key")
+	defer server.Close()
+	client := server.Client()
+	job, err := client.CreateJob(&elementalconductor.Job{
+		Input: []elementalconductor.Input{{FileInput: elementalconductor.Location{URI: "s3://mybucket/source.mov"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.CancelJob(job.GetID()); err != nil {
+		t.Fatal(err)
+	}
+	c := newTestCLI(server)
+	if code := c.run("jobs", "get", "-watch", job.GetID()); code != 1 {
+		t.Errorf("wrong exit code\nwant 1\ngot  %d", code)
+	}
+	expectedErr := "job 1 was cancelled\n"
+	if c.stderr.String() != expectedErr {
+		t.Errorf("wrong error\nwant %q\ngot  %q", expectedErr, c.stderr.String())
+	}
+}
+
+func TestCancelJobCommand(t *testing.T) {
+	server := elementalconductortest.NewServer("myuser", "secret-key")
+	defer server.Close()
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	jobFile := filepath.Join(dir, "job.json")
+	ioutil.WriteFile(jobFile, []byte(`{"Input": [{"FileInput": {"URI": "s3://mybucket/source.mov"}}]}`), 0644)
+	c := newTestCLI(server)
+	if code := c.run("jobs", "create", jobFile); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	if !strings.Contains(c.stdout.String(), "s3://mybucket/source.mov") {
+		t.Errorf("wrong output:\n%s", c.stdout.String())
+	}
+	if code := c.run("jobs", "cancel", "1"); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	if !strings.Contains(c.stdout.String(), string(elementalconductor.JobStatusCancelled)) {
+		t.Errorf("wrong output:\n%s", c.stdout.String())
+	}
+}
+
+func TestCreateJobYAML(t *testing.T) {
+	var tests = []struct {
+		name string
+		data string
+	}{
+		{
+			"field names",
+			`Input:
+  - FileInput:
+      URI: s3://mybucket/source.mov
+StreamAssembly:
+  - Name: stream_1
+    Preset: "17"
+`,
+		},
+		{
+			"snake case",
+			`input:
+  - file_input:
+      uri: s3://mybucket/source.mov
+stream_assembly:
+  - name: stream_1
+    preset: "17"
+`,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			server := elementalconductortest.NewServer("myuser", "secret-key")
+			defer server.Close()
+			dir, cleanup := tempDir(t)
+			defer cleanup()
+			jobFile := filepath.Join(dir, "job.yaml")
+			ioutil.WriteFile(jobFile, []byte(test.data), 0644)
+			c := newTestCLI(server)
+			if code := c.run("jobs", "create", jobFile); code != 0 {
+				t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+			}
+			job, err := server.Client().GetJob("1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(job.Input) != 1 || job.Input[0].FileInput.URI != "s3://mybucket/source.mov" {
+				t.Errorf("wrong job inputs: %#v", job.Input)
+			}
+			if len(job.StreamAssembly) != 1 || job.StreamAssembly[0].Name != "stream_1" || job.StreamAssembly[0].Preset != "17" {
+				t.Errorf("wrong stream assemblies: %#v", job.StreamAssembly)
+			}
+		})
+	}
+}
+
+func TestCreateJobYAMLXMLNames(t *testing.T) {
+	server := elementalconductortest.NewServer("myuser", "secret-key")
+	defer server.Close()
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	jobFile := filepath.Join(dir, "job.yaml")
+	ioutil.WriteFile(jobFile, []byte(`input:
+  file_input:
+    uri: s3://mybucket/source.mov
+stream_assembly:
+  - name: stream_1
+    preset: 17
+output_group:
+  - order: 1
+    type: file_group_settings
+    file_group_settings:
+      destination:
+        uri: s3://mybucket/output/
+    output:
+      - stream_assembly_name: stream_1
+        order: 1
+        container: mp4
+        caption_description_name: [caption_1, caption_2]
+`), 0644)
+	c := newTestCLI(server)
+	if code := c.run("jobs", "create", jobFile); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	job, err := server.Client().GetJob("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(job.StreamAssembly) != 1 || job.StreamAssembly[0].Preset != "17" {
+		t.Errorf("wrong stream assemblies: %#v", job.StreamAssembly)
+	}
+	if len(job.OutputGroup) != 1 || len(job.OutputGroup[0].Output) != 1 {
+		t.Fatalf("wrong output groups: %#v", job.OutputGroup)
+	}
+	if destination := job.OutputGroup[0].FileGroupSettings.Destination.URI; destination != "s3://mybucket/output/" {
+		t.Errorf("wrong destination\nwant %q\ngot  %q", "s3://mybucket/output/", destination)
+	}
+	expectedCaptions := []string{"caption_1", "caption_2"}
+	if captions := job.OutputGroup[0].Output[0].CaptionDescriptionNames; !reflect.DeepEqual(captions, expectedCaptions) {
+		t.Errorf("wrong caption descriptions\nwant %#v\ngot  %#v", expectedCaptions, captions)
+	}
+}
+
+func TestCreateJobYAMLUnknownField(t *testing.T) {
+	server := elementalconductortest.NewServer("myuser", "secret-key")
+	defer server.Close()
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	jobFile := filepath.Join(dir, "job.yaml")
+	ioutil.WriteFile(jobFile, []byte("stream_assembly:\n  - name: stream_1\n    preest: 17\n"), 0644)
+	c := newTestCLI(server)
+	if code := c.run("jobs", "create", jobFile); code != 1 {
+		t.Errorf("wrong exit code\nwant 1\ngot  %d", code)
+	}
+	expected := "invalid job file " + jobFile + ": unknown field stream_assembly[0].preest\n"
+	if c.stderr.String() != expected {
+		t.Errorf("wrong error\nwant %q\ngot  %q", expected, c.stderr.String())
+	}
+}
+
+func TestCreateJobInvalidYAML(t *testing.T) {
+	server := elementalconductortest.NewServer("myuser", "secret-key")
+	defer server.Close()
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	jobFile := filepath.Join(dir, "job.yml")
+	ioutil.WriteFile(jobFile, []byte("input: [\n"), 0644)
+	c := newTestCLI(server)
+	if code := c.run("jobs", "create", jobFile); code != 1 {
+		t.Errorf("wrong exit code\nwant 1\ngot  %d", code)
+	}
+	if !strings.HasPrefix(c.stderr.String(), "invalid job file "+jobFile+": yaml:") {
+		t.Errorf("wrong error: %q", c.stderr.String())
+	}
+}
+
+func TestExportImportPreset(t *testing.T) {
+	source := elementalconductortest.NewServer("myuser", "secret-key")
+	defer source.Close()
+	target := elementalconductortest.NewServer("myuser", "secret-key")
+	defer target.Close()
+	preset := source.AddPreset(elementalconductor.Preset{
+		Name:         "mp4_1080p",
+		Container:    "mp4",
+		Width:        "1920",
+		Height:       "1080",
+		VideoCodec:   "h.264",
+		VideoBitrate: "5000000",
+	})
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	presetFile := filepath.Join(dir, "preset.xml")
+
+	c := newTestCLI(source)
+	if code := c.run("presets", "export", "-o", presetFile, presetID(&preset)); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	c = newTestCLI(target)
+	if code := c.run("presets", "import", presetFile); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	if code := c.run("-json", "presets", "list"); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	var imported []elementalconductor.Preset
+	if err := json.Unmarshal(c.stdout.Bytes(), &imported); err != nil {
+		t.Fatal(err)
+	}
+	expected := preset
+	expected.XMLName = xml.Name{Local: "preset"}
+	expected.Href = "/presets/1"
+	if !reflect.DeepEqual(imported, []elementalconductor.Preset{expected}) {
+		t.Errorf("wrong presets\nwant %#v\ngot  %#v", []elementalconductor.Preset{expected}, imported)
+	}
+}
+
+func TestNodesAndCloudConfig(t *testing.T) {
+	server := elementalconductortest.NewServer("myuser", "secret-key")
+	defer server.Close()
+	c := newTestCLI(server)
+	if code := c.run("nodes", "list"); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	if lines := strings.Split(strings.TrimSpace(c.stdout.String()), "\n"); len(lines) != 4 {
+		t.Errorf("wrong nodes output:\n%s", c.stdout.String())
+	}
+	if code := c.run("-json", "cloud-config", "get"); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	var config elementalconductor.CloudConfig
+	if err := json.Unmarshal(c.stdout.Bytes(), &config); err != nil {
+		t.Fatal(err)
+	}
+	if config.AuthorizedNodeCount != 10 {
+		t.Errorf("wrong config: %#v", config)
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	env := map[string]string{"CONDUCTOR_HOST": "http://localhost", "CONDUCTOR_USER_LOGIN": "myuser", "CONDUCTOR_API_KEY": "key"}
+	var tests = []struct {
+		name string
+		env  map[string]string
+		args []string
+	}{
+		{"no command", nil, nil},
+		{"unknown command", nil, []string{"whatever"}},
+		{"missing credentials", nil, []string{"jobs", "list"}},
+		{"invalid auth expires", map[string]string{"CONDUCTOR_HOST": "http://localhost", "CONDUCTOR_USER_LOGIN": "myuser", "CONDUCTOR_API_KEY": "key", "CONDUCTOR_AUTH_EXPIRES": "soon"}, []string{"jobs", "list"}},
+		{"missing job id", env, []string{"jobs", "get"}},
+		{"unknown jobs subcommand", env, []string{"jobs", "delete", "1"}},
+		{"unknown nodes subcommand", env, []string{"nodes", "reboot"}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(test.args, func(key string) string { return test.env[key] }, &stdout, &stderr)
+			if code != 2 {
+				t.Errorf("wrong exit code\nwant 2\ngot  %d", code)
+			}
+			if stdout.Len() != 0 {
+				t.Errorf("unexpected output: %s", stdout.String())
+			}
+		})
+	}
+}
+
+func TestProgressBar(t *testing.T) {
+	var tests = []struct {
+		pct      int
+		status   elementalconductor.JobStatus
+		expected string
+	}{
+		{0, elementalconductor.JobStatusPending, "[----------------------------------------]   0% Pending       "},
+		{42, elementalconductor.JobStatusRunning, "[################------------------------]  42% Running       "},
+		{150, elementalconductor.JobStatusComplete, "[########################################] 100% Complete      "},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(string(test.status), func(t *testing.T) {
+			if got := progressBar(test.pct, test.status); got != test.expected {
+				t.Errorf("wrong progress bar\nwant %q\ngot  %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestPresetsSync(t *testing.T) {
+	server := elementalconductortest.NewServer("myuser", "secret-key")
+	defer server.Close()
+	server.AddPreset(elementalconductor.Preset{Name: "mp4_720p", Container: "mp4"})
+	dir, cleanup := tempDir(t)
+	defer cleanup()
+	ioutil.WriteFile(filepath.Join(dir, "mp4_720p.xml"), []byte(`<preset><name>mp4_720p</name><container>m3u8</container></preset>`), 0644)
+	c := newTestCLI(server)
+	expectedPlan := "~ mp4_720p\n    container: \"mp4\" => \"m3u8\"\n"
+	if code := c.run("presets", "sync", dir); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	if c.stdout.String() != expectedPlan {
+		t.Errorf("wrong output\nwant %q\ngot  %q", expectedPlan, c.stdout.String())
+	}
+	if code := c.run("presets", "sync", "-apply", dir); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	if code := c.run("presets", "sync", dir); code != 0 || c.stdout.String() != "no changes\n" {
+		t.Errorf("wrong output after applying: %q (exit code %d)", c.stdout.String(), code)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+func nodes(c *cli, args []string) error {
+	if len(args) != 1 || args[0] != "list" {
+		return errUsage
+	}
+	nodes, err := c.client.GetNodes()
+	if err != nil {
+		return err
+	}
+	return c.print(nodes, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tNAME\tHOSTNAME\tPRODUCT\tVERSION\tSTATUS\tRUNNING")
+		for i := range nodes {
+			node := &nodes[i]
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%d\n", node.GetID(), node.Name, node.HostName, node.Product, node.Version, node.Status, node.RunningCount)
+		}
+	})
+}
+
+func cloudConfig(c *cli, args []string) error {
+	if len(args) != 1 || args[0] != "get" {
+		return errUsage
+	}
+	config, err := c.client.GetCloudConfig()
+	if err != nil {
+		return err
+	}
+	return c.print(config, func(w io.Writer) {
+		fmt.Fprintf(w, "Authorized nodes:\t%d\n", config.AuthorizedNodeCount)
+		fmt.Fprintf(w, "Min cluster size:\t%d\n", config.MinNodes)
+		fmt.Fprintf(w, "Max cluster size:\t%d\n", config.MaxNodes)
+		fmt.Fprintf(w, "Worker variant:\t%s\n", config.WorkerVariant)
+	})
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+
+	"github.com/NYTimes/encoding-wrapper/elementalconductor"
+	"github.com/NYTimes/encoding-wrapper/presetsync"
+)
+
+func presets(c *cli, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	switch args[0] {
+	case "list":
+		return listPresets(c, args[1:])
+	case "get":
+		return getPreset(c, args[1:])
+	case "export":
+		return exportPreset(c, args[1:])
+	case "import":
+		return importPreset(c, args[1:])
+	case "sync":
+		return syncPresets(c, args[1:])
+	}
+	return errUsage
+}
+
+func presetID(preset *elementalconductor.Preset) string {
+	if preset.Href == "" {
+		return ""
+	}
+	return path.Base(preset.Href)
+}
+
+func listPresets(c *cli, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	list, err := c.client.GetPresets()
+	if err != nil {
+		return err
+	}
+	return c.print(list.Presets, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tNAME\tCONTAINER\tVIDEO CODEC\tSIZE\tBITRATE")
+		for i := range list.Presets {
+			preset := &list.Presets[i]
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%sx%s\t%s\n", presetID(preset), preset.Name, preset.Container, preset.VideoCodec, preset.Width, preset.Height, preset.VideoBitrate)
+		}
+	})
+}
+
+func getPreset(c *cli, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	preset, err := c.client.GetPreset(args[0])
+	if err != nil {
+		return err
+	}
+	return c.print(preset, func(w io.Writer) {
+		fmt.Fprintf(w, "ID:\t%s\n", presetID(preset))
+		fmt.Fprintf(w, "Name:\t%s\n", preset.Name)
+		fmt.Fprintf(w, "Description:\t%s\n", preset.Description)
+		fmt.Fprintf(w, "Container:\t%s\n", preset.Container)
+		fmt.Fprintf(w, "Video codec:\t%s\n", preset.VideoCodec)
+		fmt.Fprintf(w, "Size:\t%sx%s\n", preset.Width, preset.Height)
+		fmt.Fprintf(w, "Video bitrate:\t%s\n", preset.VideoBitrate)
+		fmt.Fprintf(w, "Profile:\t%s %s\n", preset.Profile, preset.ProfileLevel)
+		fmt.Fprintf(w, "Audio codec:\t%s\n", preset.AudioCodec)
+		fmt.Fprintf(w, "Audio bitrate:\t%s\n", preset.AudioBitrate)
+	})
+}
+
+// exportPreset writes the XML definition of the given preset, without its
+// server-assigned href and permalink, so it can be imported elsewhere.
+func exportPreset(c *cli, args []string) error {
+	flags := c.flagSet("presets export")
+	output := flags.String("o", "", "file to write the preset to (default: standard output)")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+	preset, err := c.client.GetPreset(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	preset.Href = ""
+	preset.Permalink = ""
+	data, err := xml.MarshalIndent(preset, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append([]byte(xml.Header), append(data, '\n')...)
+	if *output == "" {
+		_, err = c.stdout.Write(data)
+		return err
+	}
+	return ioutil.WriteFile(*output, data, 0644)
+}
+
+func importPreset(c *cli, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	var preset elementalconductor.Preset
+	if err = xml.Unmarshal(data, &preset); err != nil {
+		return fmt.Errorf("invalid preset file %s: %s", args[0], err)
+	}
+	preset.Href = ""
+	preset.Permalink = ""
+	created, err := c.client.CreatePreset(&preset)
+	if err != nil {
+		return err
+	}
+	return c.print(created, func(w io.Writer) {
+		fmt.Fprintf(w, "imported preset %s as %s\n", created.Name, presetID(created))
+	})
+}
+
+// syncPresets prints the changes needed for making the presets in the cluster
+// match the definitions in the given directory, applying them when the
+// -apply flag is set.
+func syncPresets(c *cli, args []string) error {
+	flags := c.flagSet("presets sync")
+	apply := flags.Bool("apply", false, "apply the changes instead of only printing them")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+	syncer, err := presetsync.NewElementalConductor(c.client, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	plan, err := syncer.Plan()
+	if err != nil {
+		return err
+	}
+	err = c.print(plan, func(w io.Writer) {
+		fmt.Fprint(w, plan)
+	})
+	if err != nil || !*apply {
+		return err
+	}
+	return syncer.Apply(plan)
+}
@@ -0,0 +1,165 @@
+// Command encodingcom is a command line tool for interacting with the
+// Encoding.com API, built on top of the encodingcom package.
+//
+// Credentials are read from the environment:
+//
+//	ENCODINGCOM_USER_ID     the id of the Encoding.com user (required)
+//	ENCODINGCOM_USER_KEY    the key of the Encoding.com user (required)
+//	ENCODINGCOM_ENDPOINT    the API endpoint (default: https://manage.encoding.com)
+//	ENCODINGCOM_STATUS_URL  the endpoint used by api-status (default: http://status.encoding.com)
+//
+// Run "encodingcom help" for the list of available commands.
+package main // import "github.com/NYTimes/encoding-wrapper/cmd/encodingcom"
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NYTimes/encoding-wrapper/encodingcom"
+)
+
+const (
+	defaultEndpoint  = "https://manage.encoding.com"
+	defaultStatusURL = "http://status.encoding.com"
+)
+
+var errUsage = errors.New("invalid usage")
+
+type command struct {
+	usage       string
+	description string
+	needsClient bool
+	run         func(c *cli, args []string) error
+}
+
+var commands = map[string]command{
+	"add-media":  {"add-media -format <file> [-region <region>] <source>...", "add media with the formats defined in the given JSON file", true, addMedia},
+	"status":     {"status [-watch] [-interval <duration>] [-extended=false] <media-id>...", "show the status of media", true, status},
+	"media-info": {"media-info <media-id>", "show video parameters of the given media", true, mediaInfo},
+	"cancel":     {"cancel <media-id>", "cancel the given media", true, mediaAction((*encodingcom.Client).CancelMedia)},
+	"stop":       {"stop <media-id>", "stop the given media", true, mediaAction((*encodingcom.Client).StopMedia)},
+	"restart":    {"restart [-errors] <media-id>", "restart the given media", true, restart},
+	"preset":     {"preset list [-type all|user|ui] | get <name> | save -format <file> <name> | delete <name> | sync [-apply] <dir>", "manage presets", true, preset},
+	"api-status": {"api-status", "show the status of the Encoding.com API", false, apiStatus},
+}
+
+type cli struct {
+	client    *encodingcom.Client
+	statusURL string
+	stdout    io.Writer
+	stderr    io.Writer
+	json      bool
+	sleep     func(time.Duration)
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Getenv, os.Stdout, os.Stderr))
+}
+
+func run(args []string, getenv func(string) string, stdout, stderr io.Writer) int {
+	c := cli{stdout: stdout, stderr: stderr, sleep: time.Sleep}
+	return c.run(args, getenv)
+}
+
+func (c *cli) run(args []string, getenv func(string) string) int {
+	c.statusURL = getenv("ENCODINGCOM_STATUS_URL")
+	if c.statusURL == "" {
+		c.statusURL = defaultStatusURL
+	}
+	flags := flag.NewFlagSet("encodingcom", flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	flags.BoolVar(&c.json, "json", false, "print output in JSON format")
+	flags.Usage = func() { c.usage() }
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() == 0 || flags.Arg(0) == "help" {
+		c.usage()
+		return 2
+	}
+	cmd, ok := commands[flags.Arg(0)]
+	if !ok {
+		fmt.Fprintf(c.stderr, "unknown command %q\n", flags.Arg(0))
+		c.usage()
+		return 2
+	}
+	if cmd.needsClient {
+		userID, userKey := getenv("ENCODINGCOM_USER_ID"), getenv("ENCODINGCOM_USER_KEY")
+		if userID == "" || userKey == "" {
+			fmt.Fprintln(c.stderr, "ENCODINGCOM_USER_ID and ENCODINGCOM_USER_KEY must be set")
+			return 2
+		}
+		endpoint := getenv("ENCODINGCOM_ENDPOINT")
+		if endpoint == "" {
+			endpoint = defaultEndpoint
+		}
+		c.client, _ = encodingcom.NewClient(endpoint, userID, userKey)
+	}
+	err := cmd.run(c, flags.Args()[1:])
+	if err == errUsage {
+		fmt.Fprintf(c.stderr, "usage: encodingcom %s\n", cmd.usage)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(c.stderr, err)
+		return 1
+	}
+	return 0
+}
+
+func (c *cli) usage() {
+	fmt.Fprintln(c.stderr, "usage: encodingcom [-json] <command> [arguments]\n\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	w := tabwriter.NewWriter(c.stderr, 0, 8, 2, ' ', 0)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %s\t%s\n", name, commands[name].description)
+	}
+	w.Flush()
+}
+
+func (c *cli) flagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(c.stderr)
+	return flags
+}
+
+// print writes the given value to the standard output, either in JSON format
+// or as a table, using the given function for writing the rows.
+func (c *cli) print(v interface{}, table func(w io.Writer)) error {
+	if c.json {
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(c.stdout, "%s\n", data)
+		return err
+	}
+	w := tabwriter.NewWriter(c.stdout, 0, 8, 2, ' ', 0)
+	table(w)
+	return w.Flush()
+}
+
+func apiStatus(c *cli, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	resp, err := encodingcom.APIStatus(c.statusURL)
+	if err != nil {
+		return err
+	}
+	return c.print(resp, func(w io.Writer) {
+		fmt.Fprintln(w, "STATUS\tCODE\tINCIDENT")
+		fmt.Fprintf(w, "%s\t%s\t%s\n", resp.Status, resp.StatusCode, resp.Incident)
+	})
+}
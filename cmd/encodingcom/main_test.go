@@ -0,0 +1,252 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/NYTimes/encoding-wrapper/encodingcom"
+	"github.com/NYTimes/encoding-wrapper/encodingcom/encodingcomtest"
+)
+
+type testCLI struct {
+	cli
+	env    map[string]string
+	stdout bytes.Buffer
+	stderr bytes.Buffer
+}
+
+func newTestCLI(server *encodingcomtest.Server) *testCLI {
+	c := testCLI{env: map[string]string{
+		"ENCODINGCOM_USER_ID":  "myuser",
+		"ENCODINGCOM_USER_KEY": "secret-key",
+		"ENCODINGCOM_ENDPOINT": server.URL,
+	}}
+	c.cli.stdout = &c.stdout
+	c.cli.stderr = &c.stderr
+	c.cli.sleep = server.Advance
+	return &c
+}
+
+func (c *testCLI) run(args ...string) int {
+	c.stdout.Reset()
+	c.stderr.Reset()
+	return c.cli.run(args, func(key string) string { return c.env[key] })
+}
+
+func writeFormatFile(t *testing.T, content string) (string, func()) {
+	f, err := ioutil.TempFile("", "format")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.WriteString(content)
+	return f.Name(), func() { os.Remove(f.Name()) }
+}
+
+func TestAddMediaAndWatchStatus(t *testing.T) {
+	server := encodingcomtest.NewServer()
+	defer server.Close()
+	server.SetProcessingTime(time.Minute)
+	formatFile, cleanup := writeFormatFile(t, `{"output": ["mp4"], "size": "1280x720", "destination": ["s3://mybucket/file.mp4"]}`)
+	defer cleanup()
+	c := newTestCLI(server)
+
+	if code := c.run("-json", "add-media", "-format", formatFile, "http://some.video/file.mov"); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	var added encodingcom.AddMediaResponse
+	if err := json.Unmarshal(c.stdout.Bytes(), &added); err != nil {
+		t.Fatal(err)
+	}
+	if added.MediaID != "1" {
+		t.Errorf("wrong media id\nwant %q\ngot  %q", "1", added.MediaID)
+	}
+
+	if code := c.run("status", "-watch", "-interval", "20s", "1"); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	output := c.stdout.String()
+	if n := strings.Count(output, "MEDIA ID"); n != 4 {
+		t.Errorf("wrong number of polls\nwant 4\ngot  %d\n%s", n, output)
+	}
+	if !strings.Contains(output, string(encodingcom.MediaStatusFinished)) {
+		t.Errorf("status output doesn't include the finished status:\n%s", output)
+	}
+	if !strings.Contains(output, "s3://mybucket/file.mp4") {
+		t.Errorf("status output doesn't include the destination:\n%s", output)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	server := encodingcomtest.NewServer()
+	defer server.Close()
+	server.Client().AddMedia([]string{"http://some.video/file.mov"}, []encodingcom.Format{{Output: []string{"mp4"}}}, "")
+	c := newTestCLI(server)
+	if code := c.run("cancel", "1"); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	if status, _ := server.MediaStatus("1"); status != encodingcom.MediaStatusDeleted {
+		t.Errorf("wrong status\nwant %q\ngot  %q", encodingcom.MediaStatusDeleted, status)
+	}
+	if code := c.run("cancel", "1"); code != 1 {
+		t.Errorf("wrong exit code\nwant 1\ngot  %d", code)
+	}
+	if !strings.Contains(c.stderr.String(), "Encoding.com API") {
+		t.Errorf("wrong error output: %s", c.stderr.String())
+	}
+}
+
+func TestPresetCommands(t *testing.T) {
+	server := encodingcomtest.NewServer()
+	defer server.Close()
+	formatFile, cleanup := writeFormatFile(t, `[{"output": ["webm"], "video_codec": "libvpx", "two_pass": "yes"}]`)
+	defer cleanup()
+	c := newTestCLI(server)
+	if code := c.run("preset", "save", "-format", formatFile, "my_webm"); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	if code := c.run("-json", "preset", "get", "my_webm"); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	var preset encodingcom.Preset
+	if err := json.Unmarshal(c.stdout.Bytes(), &preset); err != nil {
+		t.Fatal(err)
+	}
+	if preset.Format.VideoCodec != "libvpx" || !bool(preset.Format.TwoPass) {
+		t.Errorf("wrong preset: %#v", preset)
+	}
+	if code := c.run("preset", "list", "-type", "user"); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(c.stdout.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "my_webm") {
+		t.Errorf("wrong list output:\n%s", c.stdout.String())
+	}
+	if code := c.run("preset", "delete", "my_webm"); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	if code := c.run("preset", "get", "my_webm"); code != 1 {
+		t.Errorf("wrong exit code for deleted preset\nwant 1\ngot  %d", code)
+	}
+}
+
+func TestAPIStatusCommand(t *testing.T) {
+	statusServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(`{"status":"Encoding Queue Processing Delays","status_code":"queue_slow","incident":"Slow queue."}`))
+	}))
+	defer statusServer.Close()
+	var stdout, stderr bytes.Buffer
+	env := map[string]string{"ENCODINGCOM_STATUS_URL": statusServer.URL}
+	code := run([]string{"api-status"}, func(key string) string { return env[key] }, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, stderr.String())
+	}
+	expected := "STATUS                            CODE        INCIDENT\nEncoding Queue Processing Delays  queue_slow  Slow queue.\n"
+	if stdout.String() != expected {
+		t.Errorf("wrong output\nwant %q\ngot  %q", expected, stdout.String())
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	var tests = []struct {
+		name string
+		env  map[string]string
+		args []string
+	}{
+		{"no command", nil, nil},
+		{"unknown command", nil, []string{"whatever"}},
+		{"missing credentials", nil, []string{"status", "1"}},
+		{"missing arguments", map[string]string{"ENCODINGCOM_USER_ID": "myuser", "ENCODINGCOM_USER_KEY": "key"}, []string{"status"}},
+		{"missing format", map[string]string{"ENCODINGCOM_USER_ID": "myuser", "ENCODINGCOM_USER_KEY": "key"}, []string{"add-media", "http://some.video/file.mov"}},
+		{"unknown preset subcommand", map[string]string{"ENCODINGCOM_USER_ID": "myuser", "ENCODINGCOM_USER_KEY": "key"}, []string{"preset", "rename"}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			code := run(test.args, func(key string) string { return test.env[key] }, &stdout, &stderr)
+			if code != 2 {
+				t.Errorf("wrong exit code\nwant 2\ngot  %d", code)
+			}
+			if stdout.Len() != 0 {
+				t.Errorf("unexpected output: %s", stdout.String())
+			}
+		})
+	}
+}
+
+func TestLoadFormats(t *testing.T) {
+	var tests = []struct {
+		name    string
+		content string
+		want    []encodingcom.Format
+	}{
+		{"single", `{"output": ["mp4"], "size": "1280x720"}`, []encodingcom.Format{{Output: []string{"mp4"}, Size: "1280x720"}}},
+		{"list", `[{"output": ["mp4"]}, {"output": ["webm"]}]`, []encodingcom.Format{{Output: []string{"mp4"}}, {Output: []string{"webm"}}}},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			path, cleanup := writeFormatFile(t, test.content)
+			defer cleanup()
+			got, err := loadFormats(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Errorf("wrong formats\nwant %#v\ngot  %#v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestPresetSync(t *testing.T) {
+	server := encodingcomtest.NewServer()
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "presets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, "mp4_720p.json"), []byte(`{"output": ["mp4"], "size": "1280x720"}`), 0644)
+	c := newTestCLI(server)
+	if code := c.run("preset", "sync", dir); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	if c.stdout.String() != "+ mp4_720p\n" {
+		t.Errorf("wrong output\nwant %q\ngot  %q", "+ mp4_720p\n", c.stdout.String())
+	}
+	if _, err = server.Client().GetPreset("mp4_720p"); err == nil {
+		t.Error("unexpected <nil> error: preset created without -apply")
+	}
+	if code := c.run("preset", "sync", "-apply", dir); code != 0 {
+		t.Fatalf("wrong exit code\nwant 0\ngot  %d\nstderr: %s", code, c.stderr.String())
+	}
+	if code := c.run("preset", "sync", dir); code != 0 || c.stdout.String() != "no changes\n" {
+		t.Errorf("wrong output after applying: %q (exit code %d)", c.stdout.String(), code)
+	}
+}
+
+func TestPrintStatusUnknownTimeLeft(t *testing.T) {
+	var buf bytes.Buffer
+	printStatus(&buf, []encodingcom.StatusResponse{
+		{MediaID: "1", MediaStatus: encodingcom.MediaStatusNew, TimeLeft: encodingcom.UnknownTimeLeft},
+		{MediaID: "2", MediaStatus: encodingcom.MediaStatusProcessing, TimeLeft: 90 * time.Second},
+	})
+	lines := strings.Split(buf.String(), "\n")
+	if !strings.Contains(lines[1], "\tunknown\t") {
+		t.Errorf("unknown time left not printed as unknown: %q", lines[1])
+	}
+	if !strings.Contains(lines[2], "\t1m30s\t") {
+		t.Errorf("wrong time left: %q", lines[2])
+	}
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/NYTimes/encoding-wrapper/encodingcom"
+)
+
+// loadFormats reads the given JSON file, that may contain either a single
+// format or a list of formats.
+func loadFormats(path string) ([]encodingcom.Format, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var formats []encodingcom.Format
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(data, &formats)
+	} else {
+		formats = make([]encodingcom.Format, 1)
+		err = json.Unmarshal(data, &formats[0])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid format file %s: %s", path, err)
+	}
+	return formats, nil
+}
+
+func addMedia(c *cli, args []string) error {
+	flags := c.flagSet("add-media")
+	formatFile := flags.String("format", "", "JSON file with the output formats")
+	region := flags.String("region", "", "region of the source files")
+	if err := flags.Parse(args); err != nil || *formatFile == "" || flags.NArg() == 0 {
+		return errUsage
+	}
+	formats, err := loadFormats(*formatFile)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.AddMedia(flags.Args(), formats, *region)
+	if err != nil {
+		return err
+	}
+	return c.print(resp, func(w io.Writer) {
+		fmt.Fprintln(w, "MEDIA ID\tMESSAGE")
+		fmt.Fprintf(w, "%s\t%s\n", resp.MediaID, resp.Message)
+	})
+}
+
+func status(c *cli, args []string) error {
+	flags := c.flagSet("status")
+	watch := flags.Bool("watch", false, "keep polling until all media reach a terminal status")
+	interval := flags.Duration("interval", 10*time.Second, "polling interval used with -watch")
+	extended := flags.Bool("extended", true, "include the status of each format")
+	if err := flags.Parse(args); err != nil || flags.NArg() == 0 {
+		return errUsage
+	}
+	for {
+		resp, err := c.client.GetStatus(flags.Args(), *extended)
+		if err != nil {
+			return err
+		}
+		err = c.print(resp, func(w io.Writer) { printStatus(w, resp) })
+		if err != nil || !*watch || allTerminal(resp) {
+			return err
+		}
+		c.sleep(*interval)
+	}
+}
+
+func allTerminal(resp []encodingcom.StatusResponse) bool {
+	for _, status := range resp {
+		if !status.MediaStatus.IsTerminal() {
+			return false
+		}
+	}
+	return true
+}
+
+func printStatus(w io.Writer, resp []encodingcom.StatusResponse) {
+	fmt.Fprintln(w, "MEDIA ID\tSTATUS\tPROGRESS\tTIME LEFT\tFORMAT\tFORMAT STATUS\tDESTINATIONS")
+	for _, status := range resp {
+		fmt.Fprintf(w, "%s\t%s\t%.1f%%\t%s\t\t\t\n", status.MediaID, status.MediaStatus, status.Progress, formatTimeLeft(status.TimeLeft))
+		for _, format := range status.Formats {
+			destinations := make([]string, len(format.Destinations))
+			for i, destination := range format.Destinations {
+				destinations[i] = destination.Name
+			}
+			fmt.Fprintf(w, "\t\t\t\t%s\t%s\t%v\n", format.ID, format.Status, destinations)
+		}
+	}
+}
+
+func formatTimeLeft(d time.Duration) string {
+	if d == encodingcom.UnknownTimeLeft {
+		return "unknown"
+	}
+	return d.String()
+}
+
+func mediaInfo(c *cli, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	info, err := c.client.GetMediaInfo(args[0])
+	if err != nil {
+		return err
+	}
+	return c.print(info, func(w io.Writer) {
+		fmt.Fprintf(w, "Duration:\t%s\n", info.Duration)
+		fmt.Fprintf(w, "Size:\t%s\n", info.Size)
+		fmt.Fprintf(w, "Bitrate:\t%s\n", info.Bitrate)
+		fmt.Fprintf(w, "Frame rate:\t%s\n", info.Framerate)
+		fmt.Fprintf(w, "Video codec:\t%s\n", info.VideoCodec)
+		fmt.Fprintf(w, "Video bitrate:\t%s\n", info.VideoBitrate)
+		fmt.Fprintf(w, "Audio codec:\t%s\n", info.AudioCodec)
+		fmt.Fprintf(w, "Audio bitrate:\t%s\n", info.AudioBitrate)
+		fmt.Fprintf(w, "Audio sample rate:\t%d\n", info.AudioSampleRate)
+		fmt.Fprintf(w, "Audio channels:\t%s\n", info.AudioChannels)
+		fmt.Fprintf(w, "Rotation:\t%d\n", info.Rotation)
+	})
+}
+
+func mediaAction(action func(*encodingcom.Client, string) (*encodingcom.Response, error)) func(*cli, []string) error {
+	return func(c *cli, args []string) error {
+		if len(args) != 1 {
+			return errUsage
+		}
+		resp, err := action(c.client, args[0])
+		if err != nil {
+			return err
+		}
+		return c.printResponse(resp)
+	}
+}
+
+func restart(c *cli, args []string) error {
+	flags := c.flagSet("restart")
+	withErrors := flags.Bool("errors", false, "restart only the tasks that failed")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+	resp, err := c.client.RestartMedia(flags.Arg(0), *withErrors)
+	if err != nil {
+		return err
+	}
+	return c.printResponse(resp)
+}
+
+func (c *cli) printResponse(resp *encodingcom.Response) error {
+	return c.print(resp, func(w io.Writer) {
+		fmt.Fprintln(w, resp.Message)
+	})
+}
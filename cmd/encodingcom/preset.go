@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/NYTimes/encoding-wrapper/encodingcom"
+	"github.com/NYTimes/encoding-wrapper/presetsync"
+)
+
+func preset(c *cli, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	switch args[0] {
+	case "list":
+		return listPresets(c, args[1:])
+	case "get":
+		return getPreset(c, args[1:])
+	case "save":
+		return savePreset(c, args[1:])
+	case "delete":
+		return deletePreset(c, args[1:])
+	case "sync":
+		return syncPresets(c, args[1:])
+	}
+	return errUsage
+}
+
+func listPresets(c *cli, args []string) error {
+	flags := c.flagSet("preset list")
+	presetType := flags.String("type", string(encodingcom.AllPresets), "type of presets to list (all, user or ui)")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 0 {
+		return errUsage
+	}
+	resp, err := c.client.ListPresets(encodingcom.PresetType(*presetType))
+	if err != nil {
+		return err
+	}
+	return c.print(resp, func(w io.Writer) {
+		fmt.Fprintln(w, "NAME\tTYPE\tOUTPUT\tSIZE\tBITRATE\tVIDEO CODEC")
+		for _, presets := range [][]encodingcom.Preset{resp.UserPresets, resp.UIPresets} {
+			for _, preset := range presets {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", preset.Name, preset.Type, preset.Output, preset.Format.Size, preset.Format.Bitrate, preset.Format.VideoCodec)
+			}
+		}
+	})
+}
+
+func getPreset(c *cli, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	preset, err := c.client.GetPreset(args[0])
+	if err != nil {
+		return err
+	}
+	return c.print(preset, func(w io.Writer) {
+		fmt.Fprintf(w, "Name:\t%s\n", preset.Name)
+		fmt.Fprintf(w, "Type:\t%s\n", preset.Type)
+		fmt.Fprintf(w, "Output:\t%s\n", preset.Output)
+		fmt.Fprintf(w, "Size:\t%s\n", preset.Format.Size)
+		fmt.Fprintf(w, "Bitrate:\t%s\n", preset.Format.Bitrate)
+		fmt.Fprintf(w, "Video codec:\t%s\n", preset.Format.VideoCodec)
+		fmt.Fprintf(w, "Audio codec:\t%s\n", preset.Format.AudioCodec)
+		fmt.Fprintf(w, "Audio bitrate:\t%s\n", preset.Format.AudioBitrate)
+		fmt.Fprintf(w, "Two pass:\t%t\n", preset.Format.TwoPass)
+	})
+}
+
+func savePreset(c *cli, args []string) error {
+	flags := c.flagSet("preset save")
+	formatFile := flags.String("format", "", "JSON file with the preset format")
+	if err := flags.Parse(args); err != nil || *formatFile == "" || flags.NArg() != 1 {
+		return errUsage
+	}
+	formats, err := loadFormats(*formatFile)
+	if err != nil {
+		return err
+	}
+	if len(formats) != 1 {
+		return fmt.Errorf("the format file must define exactly one format, found %d", len(formats))
+	}
+	resp, err := c.client.SavePreset(flags.Arg(0), formats[0])
+	if err != nil {
+		return err
+	}
+	return c.print(resp, func(w io.Writer) {
+		fmt.Fprintf(w, "saved preset %s\n", resp.SavedPreset)
+	})
+}
+
+func deletePreset(c *cli, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	resp, err := c.client.DeletePreset(args[0])
+	if err != nil {
+		return err
+	}
+	return c.printResponse(resp)
+}
+
+// syncPresets prints the changes needed for making the user presets in the
+// account match the definitions in the given directory, applying them when
+// the -apply flag is set.
+func syncPresets(c *cli, args []string) error {
+	flags := c.flagSet("preset sync")
+	apply := flags.Bool("apply", false, "apply the changes instead of only printing them")
+	if err := flags.Parse(args); err != nil || flags.NArg() != 1 {
+		return errUsage
+	}
+	syncer, err := presetsync.NewEncodingCom(c.client, flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	plan, err := syncer.Plan()
+	if err != nil {
+		return err
+	}
+	err = c.print(plan, func(w io.Writer) {
+		fmt.Fprint(w, plan)
+	})
+	if err != nil || !*apply {
+		return err
+	}
+	return syncer.Apply(plan)
+}
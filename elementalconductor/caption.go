@@ -0,0 +1,111 @@
+package elementalconductor
+
+// CaptionSourceType is the type of source used by a CaptionSelector.
+type CaptionSourceType string
+
+const (
+	// EmbeddedCaptionSource is the source type for 608/708 captions
+	// embedded in the input video stream.
+	EmbeddedCaptionSource = CaptionSourceType("Embedded")
+
+	// SCCCaptionSource is the source type for Scenarist (SCC) sidecar files.
+	SCCCaptionSource = CaptionSourceType("SCC")
+
+	// SRTCaptionSource is the source type for SubRip (SRT) sidecar files.
+	SRTCaptionSource = CaptionSourceType("SRT")
+
+	// WebVTTCaptionSource is the source type for WebVTT sidecar files.
+	WebVTTCaptionSource = CaptionSourceType("WebVTT")
+
+	// TTMLCaptionSource is the source type for TTML sidecar files.
+	TTMLCaptionSource = CaptionSourceType("TTML")
+)
+
+// CaptionDestinationType is the type of destination used by a
+// CaptionDescription.
+type CaptionDestinationType string
+
+const (
+	// EmbeddedCaptionDestination passes captions through embedded in the
+	// output video stream.
+	EmbeddedCaptionDestination = CaptionDestinationType("Embedded")
+
+	// BurnInCaptionDestination renders captions on top of the output video.
+	BurnInCaptionDestination = CaptionDestinationType("Burn in")
+
+	// WebVTTCaptionDestination generates WebVTT sidecar outputs, commonly
+	// used with Apple HTTP Live Streaming outputs.
+	WebVTTCaptionDestination = CaptionDestinationType("WebVTT")
+
+	// SCCCaptionDestination generates Scenarist (SCC) sidecar outputs.
+	SCCCaptionDestination = CaptionDestinationType("SCC")
+
+	// SRTCaptionDestination generates SubRip (SRT) sidecar outputs.
+	SRTCaptionDestination = CaptionDestinationType("SRT")
+
+	// TTMLCaptionDestination generates TTML sidecar outputs.
+	TTMLCaptionDestination = CaptionDestinationType("TTML")
+)
+
+// CaptionSelector defines a source of captions in the job's input. It's
+// referenced by name in the CaptionDescription of stream assemblies.
+type CaptionSelector struct {
+	ID                     string                  `xml:"id,omitempty"`
+	Name                   string                  `xml:"name,omitempty"`
+	Order                  int                     `xml:"order,omitempty"`
+	SourceType             CaptionSourceType       `xml:"source_type,omitempty"`
+	LanguageCode           string                  `xml:"language_code,omitempty"`
+	EmbeddedSourceSettings *EmbeddedSourceSettings `xml:"embedded_source_settings,omitempty"`
+	FileSourceSettings     *FileSourceSettings     `xml:"file_source_settings,omitempty"`
+}
+
+// EmbeddedSourceSettings contains settings for selecting 608/708 captions
+// embedded in the input.
+type EmbeddedSourceSettings struct {
+	Source608ChannelNumber int    `xml:"source_608_channel_number,omitempty"`
+	Source608TrackNumber   int    `xml:"source_608_track_number,omitempty"`
+	Convert608To708        string `xml:"convert_608_to_708,omitempty"`
+	Scte20Detection        string `xml:"scte20_detection,omitempty"`
+}
+
+// FileSourceSettings contains settings for selecting captions from a
+// sidecar file (SCC, SRT, WebVTT or TTML).
+type FileSourceSettings struct {
+	SourceFile *Location `xml:"source_file,omitempty"`
+	TimeDelta  int       `xml:"time_delta,omitempty"`
+}
+
+// CaptionDescription defines how captions from a CaptionSelector should be
+// written in a stream assembly. Outputs reference it by Name.
+type CaptionDescription struct {
+	ID                          string                       `xml:"id,omitempty"`
+	Name                        string                       `xml:"name,omitempty"`
+	Order                       int                          `xml:"order,omitempty"`
+	CaptionSourceName           string                       `xml:"caption_source_name,omitempty"`
+	DestinationType             CaptionDestinationType       `xml:"destination_type,omitempty"`
+	LanguageCode                string                       `xml:"language_code,omitempty"`
+	LanguageDescription         string                       `xml:"language_description,omitempty"`
+	EmbeddedDestinationSettings *EmbeddedDestinationSettings `xml:"embedded_destination_settings,omitempty"`
+	BurnInDestinationSettings   *BurnInDestinationSettings   `xml:"burn_in_destination_settings,omitempty"`
+}
+
+// EmbeddedDestinationSettings contains settings for captions embedded in
+// the output video stream.
+type EmbeddedDestinationSettings struct {
+	Destination608ChannelNumber int `xml:"destination_608_channel_number,omitempty"`
+}
+
+// BurnInDestinationSettings contains settings for captions rendered on top
+// of the output video.
+type BurnInDestinationSettings struct {
+	Alignment         string `xml:"alignment,omitempty"`
+	BackgroundColor   string `xml:"background_color,omitempty"`
+	BackgroundOpacity int    `xml:"background_opacity,omitempty"`
+	FontColor         string `xml:"font_color,omitempty"`
+	FontOpacity       int    `xml:"font_opacity,omitempty"`
+	FontSize          string `xml:"font_size,omitempty"`
+	OutlineColor      string `xml:"outline_color,omitempty"`
+	OutlineSize       int    `xml:"outline_size,omitempty"`
+	XPosition         int    `xml:"x_position,omitempty"`
+	YPosition         int    `xml:"y_position,omitempty"`
+}
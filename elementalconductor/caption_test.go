@@ -0,0 +1,212 @@
+package elementalconductor
+
+import (
+	"encoding/xml"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestCreateJobWithCaptions(t *testing.T) {
+	jobResponseXML := `<job href="/jobs/1">
+    <input>
+        <file_input>
+            <uri>http://another.non.existent/video.mp4</uri>
+        </file_input>
+        <caption_selector>
+            <name>Captions Selector 1</name>
+            <order>1</order>
+            <source_type>Embedded</source_type>
+            <embedded_source_settings>
+                <source_608_channel_number>1</source_608_channel_number>
+                <convert_608_to_708>Upconvert</convert_608_to_708>
+            </embedded_source_settings>
+        </caption_selector>
+        <caption_selector>
+            <name>Captions Selector 2</name>
+            <order>2</order>
+            <source_type>SCC</source_type>
+            <file_source_settings>
+                <source_file>
+                    <uri>http://another.non.existent/video.scc</uri>
+                </source_file>
+            </file_source_settings>
+        </caption_selector>
+    </input>
+    <output_group>
+        <order>1</order>
+        <apple_live_group_settings>
+            <destination>
+                <uri>http://destination/video</uri>
+            </destination>
+            <segment_length>6</segment_length>
+        </apple_live_group_settings>
+        <type>apple_live_group_settings</type>
+        <output>
+            <stream_assembly_name>stream_1</stream_assembly_name>
+            <order>1</order>
+            <container>m3u8</container>
+        </output>
+        <output>
+            <stream_assembly_name>stream_1</stream_assembly_name>
+            <name_modifier>_captions</name_modifier>
+            <order>2</order>
+            <container>m3u8</container>
+            <caption_description_name>caption_description_2</caption_description_name>
+        </output>
+    </output_group>
+    <stream_assembly>
+        <name>stream_1</name>
+        <preset>17</preset>
+        <caption_description>
+            <name>caption_description_1</name>
+            <order>1</order>
+            <caption_source_name>Captions Selector 1</caption_source_name>
+            <destination_type>Embedded</destination_type>
+            <language_code>eng</language_code>
+            <language_description>English</language_description>
+            <embedded_destination_settings>
+                <destination_608_channel_number>1</destination_608_channel_number>
+            </embedded_destination_settings>
+        </caption_description>
+        <caption_description>
+            <name>caption_description_2</name>
+            <order>2</order>
+            <caption_source_name>Captions Selector 2</caption_source_name>
+            <destination_type>WebVTT</destination_type>
+            <language_code>eng</language_code>
+        </caption_description>
+        <caption_description>
+            <name>caption_description_3</name>
+            <order>3</order>
+            <caption_source_name>Captions Selector 2</caption_source_name>
+            <destination_type>Burn in</destination_type>
+            <burn_in_destination_settings>
+                <alignment>centered</alignment>
+                <font_color>white</font_color>
+                <font_opacity>255</font_opacity>
+                <font_size>auto</font_size>
+                <outline_color>black</outline_color>
+                <outline_size>2</outline_size>
+            </burn_in_destination_settings>
+        </caption_description>
+    </stream_assembly>
+</job>`
+	server, requests := startServer(http.StatusCreated, jobResponseXML)
+	defer server.Close()
+	jobInput := Job{
+		XMLName: xml.Name{
+			Local: "job",
+		},
+		Href: "/jobs/1",
+		Input: []Input{
+			{
+				FileInput: Location{
+					URI: "http://another.non.existent/video.mp4",
+				},
+				CaptionSelector: []CaptionSelector{
+					{
+						Name:       "Captions Selector 1",
+						Order:      1,
+						SourceType: EmbeddedCaptionSource,
+						EmbeddedSourceSettings: &EmbeddedSourceSettings{
+							Source608ChannelNumber: 1,
+							Convert608To708:        "Upconvert",
+						},
+					},
+					{
+						Name:       "Captions Selector 2",
+						Order:      2,
+						SourceType: SCCCaptionSource,
+						FileSourceSettings: &FileSourceSettings{
+							SourceFile: &Location{URI: "http://another.non.existent/video.scc"},
+						},
+					},
+				},
+			},
+		},
+		OutputGroup: []OutputGroup{
+			{
+				Order: 1,
+				AppleLiveGroupSettings: &AppleLiveGroupSettings{
+					Destination:     &Location{URI: "http://destination/video"},
+					SegmentDuration: 6,
+				},
+				Type: AppleLiveOutputGroupType,
+				Output: []Output{
+					{
+						StreamAssemblyName: "stream_1",
+						Order:              1,
+						Container:          AppleHTTPLiveStreaming,
+					},
+					{
+						StreamAssemblyName:      "stream_1",
+						NameModifier:            "_captions",
+						Order:                   2,
+						Container:               AppleHTTPLiveStreaming,
+						CaptionDescriptionNames: []string{"caption_description_2"},
+					},
+				},
+			},
+		},
+		StreamAssembly: []StreamAssembly{
+			{
+				Name:   "stream_1",
+				Preset: "17",
+				CaptionDescription: []CaptionDescription{
+					{
+						Name:                "caption_description_1",
+						Order:               1,
+						CaptionSourceName:   "Captions Selector 1",
+						DestinationType:     EmbeddedCaptionDestination,
+						LanguageCode:        "eng",
+						LanguageDescription: "English",
+						EmbeddedDestinationSettings: &EmbeddedDestinationSettings{
+							Destination608ChannelNumber: 1,
+						},
+					},
+					{
+						Name:              "caption_description_2",
+						Order:             2,
+						CaptionSourceName: "Captions Selector 2",
+						DestinationType:   WebVTTCaptionDestination,
+						LanguageCode:      "eng",
+					},
+					{
+						Name:              "caption_description_3",
+						Order:             3,
+						CaptionSourceName: "Captions Selector 2",
+						DestinationType:   BurnInCaptionDestination,
+						BurnInDestinationSettings: &BurnInDestinationSettings{
+							Alignment:    "centered",
+							FontColor:    "white",
+							FontOpacity:  255,
+							FontSize:     "auto",
+							OutlineColor: "black",
+							OutlineSize:  2,
+						},
+					},
+				},
+			},
+		},
+	}
+	client := NewClient(server.URL, "myuser", "secret-key", 45, "aws-access-key", "aws-secret-key", "destination")
+
+	postJobResponse, err := client.CreateJob(&jobInput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(postJobResponse, &jobInput) {
+		t.Errorf("wrong response\nwant %#v\ngot  %#v", &jobInput, postJobResponse)
+	}
+
+	fakeReq := <-requests
+	var sentJob Job
+	err = xml.Unmarshal(fakeReq.body, &sentJob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sentJob, jobInput) {
+		t.Errorf("wrong job sent\nwant %#v\ngot  %#v", jobInput, sentJob)
+	}
+}
@@ -20,6 +20,9 @@ import (
 
 // Client is the basic type for interacting with the API. It provides methods
 // matching the available actions in the API.
+//
+// HTTPClient is the client used for sending requests to the API,
+// http.DefaultClient is used when it's nil.
 type Client struct {
 	Host            string
 	UserLogin       string
@@ -28,6 +31,7 @@ type Client struct {
 	AccessKeyID     string
 	SecretAccessKey string
 	Destination     string
+	HTTPClient      *http.Client
 }
 
 // APIError represents an error returned by the Elemental Cloud REST API.
@@ -60,6 +64,13 @@ func NewClient(host, userLogin, apiKey string, authExpires int, accessKeyID stri
 	}
 }
 
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
+}
+
 func getUnixTimestamp(givenTime time.Time) string {
 	return strconv.FormatInt(givenTime.UTC().Unix(), 10)
 }
@@ -81,7 +92,7 @@ func (c *Client) do(method string, path string, body interface{}, out interface{
 	req.Header.Set("X-Auth-User", c.UserLogin)
 	req.Header.Set("X-Auth-Expires", expiresTimestamp)
 	req.Header.Set("X-Auth-Key", c.createAuthKey(path, expiresTime))
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := c.httpClient().Do(req)
 
 	if err != nil {
 		return err
@@ -65,11 +65,13 @@ func TestDoRequiredParameters(t *testing.T) {
 		XMLName: xml.Name{
 			Local: "job",
 		},
-		Input: Input{
-			FileInput: Location{
-				URI:      "http://another.non.existent/video.mp4",
-				Username: "user",
-				Password: "pass123",
+		Input: []Input{
+			{
+				FileInput: Location{
+					URI:      "http://another.non.existent/video.mp4",
+					Username: "user",
+					Password: "pass123",
+				},
 			},
 		},
 	}
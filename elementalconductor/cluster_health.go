@@ -0,0 +1,91 @@
+package elementalconductor
+
+// ClusterHealth summarizes the health of the nodes in an Elemental setup.
+type ClusterHealth struct {
+	// TotalNodes is the number of nodes in the cluster.
+	TotalNodes int
+
+	// HealthyNodes is the number of nodes with a healthy status.
+	HealthyNodes int
+
+	// ByStatus contains the number of nodes for each status.
+	ByStatus map[NodeStatus]int
+
+	// ByProduct contains the number of nodes for each product.
+	ByProduct map[NodeProduct]int
+
+	// ByVersion contains the number of nodes for each version.
+	ByVersion map[string]int
+
+	// ExpectedVersion is the version that all nodes should be running. It's
+	// the version of the Conductor node, or the most common version when
+	// there's no Conductor node in the cluster.
+	ExpectedVersion string
+
+	// UnhealthyNodes is the list of nodes with an unhealthy status.
+	UnhealthyNodes []Node
+
+	// VersionMismatch is the list of nodes whose version doesn't match
+	// ExpectedVersion.
+	VersionMismatch []Node
+}
+
+// Healthy returns whether all nodes in the cluster are healthy and running
+// the same version.
+func (h *ClusterHealth) Healthy() bool {
+	return len(h.UnhealthyNodes) == 0 && len(h.VersionMismatch) == 0
+}
+
+// GetClusterHealth uses GetNodes to build a summary of the health of the
+// nodes currently available in the Elemental setup.
+func (c *Client) GetClusterHealth() (*ClusterHealth, error) {
+	nodes, err := c.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+	return NewClusterHealth(nodes), nil
+}
+
+// NewClusterHealth builds a summary of the health of the given nodes.
+func NewClusterHealth(nodes []Node) *ClusterHealth {
+	health := ClusterHealth{
+		TotalNodes: len(nodes),
+		ByStatus:   make(map[NodeStatus]int),
+		ByProduct:  make(map[NodeProduct]int),
+		ByVersion:  make(map[string]int),
+	}
+	for _, node := range nodes {
+		health.ByStatus[node.Status]++
+		health.ByProduct[node.Product]++
+		health.ByVersion[node.Version]++
+		if node.Status.Healthy() {
+			health.HealthyNodes++
+		} else {
+			health.UnhealthyNodes = append(health.UnhealthyNodes, node)
+		}
+		if node.Product == ProductConductorFile && health.ExpectedVersion == "" {
+			health.ExpectedVersion = node.Version
+		}
+	}
+	if health.ExpectedVersion == "" {
+		health.ExpectedVersion = mostCommonVersion(nodes, health.ByVersion)
+	}
+	for _, node := range nodes {
+		if node.Version != health.ExpectedVersion {
+			health.VersionMismatch = append(health.VersionMismatch, node)
+		}
+	}
+	return &health
+}
+
+// mostCommonVersion returns the version with more nodes, breaking ties with
+// the order of the nodes.
+func mostCommonVersion(nodes []Node, byVersion map[string]int) string {
+	var version string
+	for _, node := range nodes {
+		if byVersion[node.Version] > byVersion[version] {
+			version = node.Version
+		}
+	}
+	return version
+}
@@ -0,0 +1,121 @@
+package elementalconductor
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestNodeStatusHealthy(t *testing.T) {
+	var tests = []struct {
+		status  NodeStatus
+		healthy bool
+	}{
+		{NodeStatusActive, true},
+		{NodeStatusIdle, true},
+		{NodeStatusBusy, true},
+		{NodeStatusError, false},
+		{NodeStatusOffline, false},
+		{NodeStatusProvisioning, false},
+		{NodeStatus("whatever"), false},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(string(test.status), func(t *testing.T) {
+			if got := test.status.Healthy(); got != test.healthy {
+				t.Errorf("wrong healthy value\nwant %v\ngot  %v", test.healthy, got)
+			}
+		})
+	}
+}
+
+func TestGetClusterHealth(t *testing.T) {
+	server, _ := startServer(http.StatusOK, `<?xml version="1.0" encoding="UTF-8"?>
+<node_list>
+  <node href="/nodes/1">
+    <name>Conductor</name>
+    <status>active</status>
+    <product>Conductor File</product>
+    <version>2.7.2</version>
+  </node>
+  <node href="/nodes/31">
+    <name>Node 1</name>
+    <status>busy</status>
+    <product>Server</product>
+    <version>2.7.2</version>
+  </node>
+  <node href="/nodes/40">
+    <name>Node 2</name>
+    <status>error</status>
+    <product>Server</product>
+    <version>2.7.1</version>
+  </node>
+  <node href="/nodes/41">
+    <name>Node 3</name>
+    <status>idle</status>
+    <product>Server</product>
+    <version>2.7.2</version>
+  </node>
+</node_list>`)
+	defer server.Close()
+	client := NewClient(server.URL, "myuser", "secret-key", 45, "aws-access-key", "aws-secret-key", "destination")
+	health, err := client.GetClusterHealth()
+	if err != nil {
+		t.Fatal(err)
+	}
+	node2 := Node{Href: "/nodes/40", Name: "Node 2", Status: NodeStatusError, Product: ProductServer, Version: "2.7.1"}
+	expected := ClusterHealth{
+		TotalNodes:   4,
+		HealthyNodes: 3,
+		ByStatus: map[NodeStatus]int{
+			NodeStatusActive: 1,
+			NodeStatusBusy:   1,
+			NodeStatusError:  1,
+			NodeStatusIdle:   1,
+		},
+		ByProduct: map[NodeProduct]int{
+			ProductConductorFile: 1,
+			ProductServer:        3,
+		},
+		ByVersion: map[string]int{
+			"2.7.2": 3,
+			"2.7.1": 1,
+		},
+		ExpectedVersion: "2.7.2",
+		UnhealthyNodes:  []Node{node2},
+		VersionMismatch: []Node{node2},
+	}
+	if !reflect.DeepEqual(*health, expected) {
+		t.Errorf("wrong cluster health\nwant %#v\ngot  %#v", expected, *health)
+	}
+	if health.Healthy() {
+		t.Error("unexpected healthy cluster")
+	}
+}
+
+func TestNewClusterHealthWithoutConductor(t *testing.T) {
+	nodes := []Node{
+		{Name: "Node 1", Status: NodeStatusActive, Product: ProductServer, Version: "2.7.1"},
+		{Name: "Node 2", Status: NodeStatusActive, Product: ProductServer, Version: "2.7.2"},
+		{Name: "Node 3", Status: NodeStatusActive, Product: ProductServer, Version: "2.7.2"},
+	}
+	health := NewClusterHealth(nodes)
+	if expectedVersion := "2.7.2"; health.ExpectedVersion != expectedVersion {
+		t.Errorf("wrong expected version\nwant %q\ngot  %q", expectedVersion, health.ExpectedVersion)
+	}
+	expectedMismatch := []Node{nodes[0]}
+	if !reflect.DeepEqual(health.VersionMismatch, expectedMismatch) {
+		t.Errorf("wrong version mismatch\nwant %#v\ngot  %#v", expectedMismatch, health.VersionMismatch)
+	}
+}
+
+func TestNewClusterHealthHealthy(t *testing.T) {
+	nodes := []Node{
+		{Name: "Conductor", Status: NodeStatusActive, Product: ProductConductorFile, Version: "2.7.2"},
+		{Name: "Node 1", Status: NodeStatusIdle, Product: ProductServer, Version: "2.7.2"},
+	}
+	health := NewClusterHealth(nodes)
+	if !health.Healthy() {
+		t.Errorf("unexpected unhealthy cluster: %#v", health)
+	}
+}
@@ -1,6 +1,9 @@
 package elementalconductor
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"fmt"
+)
 
 // CloudConfig contains configuration for Elemental Cloud, including Autoscaler
 // Settings.
@@ -19,3 +22,38 @@ func (c *Client) GetCloudConfig() (*CloudConfig, error) {
 	err := c.do("GET", "/config/cloud", nil, &config)
 	return &config, err
 }
+
+// UpdateCloudConfig updates the Elemental Cloud configuration, including
+// Autoscaler Settings, returning the configuration after the update.
+//
+// The configuration is validated before being sent to the API, see
+// CloudConfig.Validate for details.
+func (c *Client) UpdateCloudConfig(config *CloudConfig) (*CloudConfig, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	var result CloudConfig
+	err := c.do("PUT", "/config/cloud", config, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Validate checks whether the Autoscaler Settings in the configuration are
+// consistent, ensuring that MinNodes <= MaxNodes <= AuthorizedNodeCount.
+//
+// AuthorizedNodeCount is not checked when it's zero, as it may be unknown by
+// the caller.
+func (cfg *CloudConfig) Validate() error {
+	if cfg.MinNodes < 0 {
+		return fmt.Errorf("invalid cloud config: min_cluster_size (%d) must not be negative", cfg.MinNodes)
+	}
+	if cfg.MinNodes > cfg.MaxNodes {
+		return fmt.Errorf("invalid cloud config: min_cluster_size (%d) must not be greater than max_cluster_size (%d)", cfg.MinNodes, cfg.MaxNodes)
+	}
+	if cfg.AuthorizedNodeCount > 0 && cfg.MaxNodes > cfg.AuthorizedNodeCount {
+		return fmt.Errorf("invalid cloud config: max_cluster_size (%d) must not be greater than authorized_node_count (%d)", cfg.MaxNodes, cfg.AuthorizedNodeCount)
+	}
+	return nil
+}
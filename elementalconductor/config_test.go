@@ -41,3 +41,98 @@ func TestGetCloudConfig(t *testing.T) {
 		t.Errorf("wrong request path\nwant %q\ngot  %q", expectedPath, fakeReq.req.URL.Path)
 	}
 }
+
+func TestUpdateCloudConfig(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<cloud_config>
+  <authorized_node_count>500</authorized_node_count>
+  <max_cluster_size>50</max_cluster_size>
+  <min_cluster_size>10</min_cluster_size>
+  <worker_variant>production_server_cloud</worker_variant>
+</cloud_config>`
+	server, requests := startServer(http.StatusOK, data)
+	defer server.Close()
+	client := NewClient(server.URL, "myuser", "secret-key", 45, "aws-access-key", "aws-secret-key", "destination")
+	newConfig := CloudConfig{
+		AuthorizedNodeCount: 500,
+		MaxNodes:            50,
+		MinNodes:            10,
+		WorkerVariant:       "production_server_cloud",
+	}
+	config, err := client.UpdateCloudConfig(&newConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedConfig := newConfig
+	expectedConfig.XMLName = xml.Name{Local: "cloud_config"}
+	if !reflect.DeepEqual(*config, expectedConfig) {
+		t.Errorf("wrong config returned\nwant %#v\ngot  %#v", expectedConfig, *config)
+	}
+
+	fakeReq := <-requests
+	if fakeReq.req.Method != http.MethodPut {
+		t.Errorf("wrong http method\nwant %q\ngot  %q", http.MethodPut, fakeReq.req.Method)
+	}
+	if expectedPath := "/api/config/cloud"; fakeReq.req.URL.Path != expectedPath {
+		t.Errorf("wrong request path\nwant %q\ngot  %q", expectedPath, fakeReq.req.URL.Path)
+	}
+	var sentConfig CloudConfig
+	err = xml.Unmarshal(fakeReq.body, &sentConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sentConfig, expectedConfig) {
+		t.Errorf("wrong config sent\nwant %#v\ngot  %#v", expectedConfig, sentConfig)
+	}
+}
+
+func TestUpdateCloudConfigValidation(t *testing.T) {
+	var tests = []struct {
+		name        string
+		config      CloudConfig
+		expectedErr string
+	}{
+		{
+			"negative min",
+			CloudConfig{MinNodes: -1, MaxNodes: 10, AuthorizedNodeCount: 500},
+			"invalid cloud config: min_cluster_size (-1) must not be negative",
+		},
+		{
+			"min greater than max",
+			CloudConfig{MinNodes: 20, MaxNodes: 10, AuthorizedNodeCount: 500},
+			"invalid cloud config: min_cluster_size (20) must not be greater than max_cluster_size (10)",
+		},
+		{
+			"max greater than authorized",
+			CloudConfig{MinNodes: 20, MaxNodes: 600, AuthorizedNodeCount: 500},
+			"invalid cloud config: max_cluster_size (600) must not be greater than authorized_node_count (500)",
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			server, requests := startServer(http.StatusOK, "")
+			defer server.Close()
+			client := NewClient(server.URL, "myuser", "secret-key", 45, "aws-access-key", "aws-secret-key", "destination")
+			config, err := client.UpdateCloudConfig(&test.config)
+			if config != nil {
+				t.Errorf("unexpected non-nil config: %#v", config)
+			}
+			if err == nil || err.Error() != test.expectedErr {
+				t.Errorf("wrong error returned\nwant %q\ngot  %v", test.expectedErr, err)
+			}
+			select {
+			case req := <-requests:
+				t.Errorf("unexpected request sent to the API: %#v", req.req)
+			default:
+			}
+		})
+	}
+}
+
+func TestCloudConfigValidateUnknownAuthorizedCount(t *testing.T) {
+	config := CloudConfig{MinNodes: 2, MaxNodes: 1000}
+	if err := config.Validate(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,695 @@
+// Package elementalconductortest provides a fake implementation of the
+// Elemental Conductor REST API, for testing code that depends on the
+// elementalconductor package.
+//
+// The fake server emulates the /jobs, /presets, /nodes and /config/cloud
+// endpoints. Jobs move through the Pending, Preprocessing, Running and
+// Postprocessing statuses until they're Complete (see SetProcessingTime and
+// Advance). Every request is authenticated by verifying the X-Auth-Key header
+// the same way the Conductor does, and failures can be injected with
+// InjectFault and FailJob.
+package elementalconductortest // import "github.com/NYTimes/encoding-wrapper/elementalconductor/elementalconductortest"
+
+import (
+	// #nosec
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NYTimes/encoding-wrapper/elementalconductor"
+)
+
+// DefaultProcessingTime is the time it takes for jobs to complete in the fake
+// server, unless changed with SetProcessingTime.
+const DefaultProcessingTime = 10 * time.Second
+
+// Fault describes a failure to be injected in the fake server. Requests
+// matching Method and Path (the path without the /api prefix, e.g.
+// "/jobs/1") get a response with the given StatusCode and Body. Empty Method
+// or Path match any request.
+//
+// Times is the number of requests that should fail, zero means that requests
+// fail until ClearFaults is called.
+type Fault struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+	Times      int
+}
+
+// Server is a fake Elemental Conductor server. It should be created with
+// NewServer and closed with Close.
+type Server struct {
+	// URL is the host of the fake server, to be used when creating
+	// elementalconductor clients.
+	URL string
+
+	userLogin      string
+	apiKey         string
+	server         *httptest.Server
+	mu             sync.Mutex
+	offset         time.Duration
+	processingTime time.Duration
+	faults         []*Fault
+	jobs           map[int]*job
+	lastJobID      int
+	presets        map[int]elementalconductor.Preset
+	lastPresetID   int
+	nodes          []elementalconductor.Node
+	cloudConfig    elementalconductor.CloudConfig
+}
+
+type job struct {
+	job          elementalconductor.Job
+	nodeID       string
+	submitted    time.Time
+	stoppedAt    time.Time
+	status       elementalconductor.JobStatus
+	errorMessage string
+}
+
+// NewServer creates and starts a new fake server, accepting requests
+// authenticated with the given user login and API key.
+//
+// The server starts with a Conductor node and two Server nodes, and with
+// Autoscaler Settings allowing up to 5 nodes.
+func NewServer(userLogin, apiKey string) *Server {
+	s := Server{
+		userLogin:      userLogin,
+		apiKey:         apiKey,
+		processingTime: DefaultProcessingTime,
+		jobs:           make(map[int]*job),
+		presets:        make(map[int]elementalconductor.Preset),
+		cloudConfig: elementalconductor.CloudConfig{
+			AuthorizedNodeCount: 10,
+			MaxNodes:            5,
+			MinNodes:            1,
+			WorkerVariant:       "production_server_cloud",
+		},
+	}
+	createdAt := elementalconductor.DateTime{Time: time.Now().UTC().Truncate(time.Second)}
+	s.nodes = []elementalconductor.Node{
+		newNode(1, "Conductor", elementalconductor.ProductConductorFile, elementalconductor.NodeStatusActive, createdAt),
+		newNode(2, "Server 1", elementalconductor.ProductServer, elementalconductor.NodeStatusIdle, createdAt),
+		newNode(3, "Server 2", elementalconductor.ProductServer, elementalconductor.NodeStatusIdle, createdAt),
+	}
+	s.server = httptest.NewServer(&s)
+	s.URL = s.server.URL
+	return &s
+}
+
+func newNode(id int, name string, product elementalconductor.NodeProduct, status elementalconductor.NodeStatus, createdAt elementalconductor.DateTime) elementalconductor.Node {
+	return elementalconductor.Node{
+		Href:      "/nodes/" + strconv.Itoa(id),
+		Name:      name,
+		HostName:  strings.ToLower(strings.Replace(name, " ", "-", -1)),
+		IPAddress: "10.0.0." + strconv.Itoa(id),
+		Eth0Mac:   fmt.Sprintf("0a:00:00:00:00:%02x", id),
+		Status:    status,
+		Product:   product,
+		Version:   "2.7.2",
+		Platform:  "Linux",
+		CreatedAt: createdAt,
+	}
+}
+
+// Client returns an elementalconductor client pointing to the fake server,
+// with the credentials accepted by the server.
+func (s *Server) Client() *elementalconductor.Client {
+	return elementalconductor.NewClient(s.URL, s.userLogin, s.apiKey, 45, "aws-access-key", "aws-secret-key", "s3://destination/")
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// SetProcessingTime defines how long jobs created in the server take to
+// complete. Jobs complete immediately when d is zero or negative.
+func (s *Server) SetProcessingTime(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d < 0 {
+		d = 0
+	}
+	s.processingTime = d
+}
+
+// Advance moves the clock of the server forward, simulating the progress of
+// jobs.
+func (s *Server) Advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset += d
+}
+
+// InjectFault makes the server fail requests as described by the given
+// fault.
+func (s *Server) InjectFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes all faults injected with InjectFault.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// FailJob moves the given job to the Error status, with the given error
+// message. It returns false if the job doesn't exist or has already
+// finished.
+func (s *Server) FailJob(jobID, message string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j := s.findJob(jobID)
+	if j == nil || !s.stop(j, elementalconductor.JobStatusError) {
+		return false
+	}
+	j.errorMessage = message
+	return true
+}
+
+// AddPreset stores the given preset in the server, returning the stored
+// preset, with its Href.
+func (s *Server) AddPreset(preset elementalconductor.Preset) elementalconductor.Preset {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addPreset(preset)
+}
+
+// AddNode adds the given node to the server, returning the stored node, with
+// its Href.
+func (s *Server) AddNode(node elementalconductor.Node) elementalconductor.Node {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	node.Href = "/nodes/" + strconv.Itoa(len(s.nodes)+1)
+	s.nodes = append(s.nodes, node)
+	return node
+}
+
+// ServeHTTP handles requests to the fake server.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !strings.HasPrefix(r.URL.Path, "/api/") {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/api")
+	if err := s.authenticate(r, path); err != nil {
+		writeError(w, http.StatusUnauthorized, err.Error())
+		return
+	}
+	if fault := s.fault(r.Method, path); fault != nil {
+		w.WriteHeader(fault.StatusCode)
+		w.Write([]byte(fault.Body))
+		return
+	}
+	body, _ := ioutil.ReadAll(r.Body)
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	var (
+		status = http.StatusOK
+		resp   interface{}
+		err    error
+	)
+	switch {
+	case route(r, parts, "GET", "jobs"):
+		resp = s.jobList(func(*job) bool { return true })
+	case route(r, parts, "POST", "jobs"):
+		status = http.StatusCreated
+		resp, err = s.createJob(body)
+	case route(r, parts, "GET", "jobs", "*"):
+		resp, err = s.getJob(parts[1])
+	case route(r, parts, "POST", "jobs", "*", "cancel"):
+		resp, err = s.cancelJob(parts[1])
+	case route(r, parts, "GET", "presets"):
+		resp = s.presetList()
+	case route(r, parts, "POST", "presets"):
+		status = http.StatusCreated
+		resp, err = s.createPreset(body)
+	case route(r, parts, "GET", "presets", "*"):
+		resp, err = s.getPreset(parts[1])
+	case route(r, parts, "PUT", "presets", "*"):
+		resp, err = s.updatePreset(parts[1], body)
+	case route(r, parts, "DELETE", "presets", "*"):
+		err = s.deletePreset(parts[1])
+	case route(r, parts, "GET", "nodes"):
+		resp = nodeList{Nodes: s.nodes}
+	case route(r, parts, "GET", "nodes", "*"):
+		resp, err = s.getNode(parts[1])
+	case route(r, parts, "GET", "nodes", "*", "jobs"):
+		resp, err = s.getNodeJobs(parts[1])
+	case route(r, parts, "POST", "nodes", "*", "*"):
+		resp, err = s.nodeAction(parts[1], parts[2])
+	case route(r, parts, "GET", "config", "cloud"):
+		resp = s.cloudConfig
+	case route(r, parts, "PUT", "config", "cloud"):
+		resp, err = s.updateCloudConfig(body)
+	default:
+		err = &httpError{status: http.StatusNotFound, message: "not found"}
+	}
+	if err != nil {
+		if httpErr, ok := err.(*httpError); ok {
+			writeError(w, httpErr.status, httpErr.message)
+		} else {
+			writeError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	if resp != nil {
+		data, _ := xml.MarshalIndent(resp, "", "  ")
+		w.Write([]byte(xml.Header))
+		w.Write(data)
+	}
+}
+
+type httpError struct {
+	status  int
+	message string
+}
+
+func (err *httpError) Error() string {
+	return err.message
+}
+
+func notFound(kind, id string) error {
+	return &httpError{status: http.StatusNotFound, message: fmt.Sprintf("%s %s not found", kind, id)}
+}
+
+func unprocessable(message string) error {
+	return &httpError{status: http.StatusUnprocessableEntity, message: message}
+}
+
+type errorList struct {
+	XMLName xml.Name `xml:"errors"`
+	Errors  []string `xml:"error"`
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	data, _ := xml.Marshal(errorList{Errors: []string{message}})
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	w.Write([]byte(xml.Header))
+	w.Write(data)
+}
+
+// route checks whether the request matches the given method and path parts,
+// where "*" matches any part.
+func route(r *http.Request, parts []string, method string, pattern ...string) bool {
+	if r.Method != method || len(parts) != len(pattern) {
+		return false
+	}
+	for i, part := range pattern {
+		if part != "*" && part != parts[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// authenticate verifies the authentication headers, using the same algorithm
+// as the Elemental Conductor: the key is md5(apiKey + md5(path + user +
+// apiKey + expires)).
+func (s *Server) authenticate(r *http.Request, path string) error {
+	user := r.Header.Get("X-Auth-User")
+	expires := r.Header.Get("X-Auth-Expires")
+	key := r.Header.Get("X-Auth-Key")
+	if user == "" || expires == "" || key == "" {
+		return errors.New("missing authentication headers")
+	}
+	if user != s.userLogin {
+		return fmt.Errorf("invalid user %q", user)
+	}
+	expiresTimestamp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid X-Auth-Expires: %q", expires)
+	}
+	if time.Unix(expiresTimestamp, 0).Before(time.Now()) {
+		return errors.New("authentication expired")
+	}
+	if key != AuthKey(path, s.userLogin, s.apiKey, expires) {
+		return errors.New("invalid X-Auth-Key")
+	}
+	return nil
+}
+
+// AuthKey computes the value of the X-Auth-Key header for the given path
+// (without the /api prefix), user login, API key and expiration timestamp.
+func AuthKey(path, userLogin, apiKey, expires string) string {
+	// #nosec
+	hasher := md5.New()
+	hasher.Write([]byte(path))
+	hasher.Write([]byte(userLogin))
+	hasher.Write([]byte(apiKey))
+	hasher.Write([]byte(expires))
+	innerKey := hex.EncodeToString(hasher.Sum(nil))
+	// #nosec
+	hasher = md5.New()
+	hasher.Write([]byte(apiKey))
+	hasher.Write([]byte(innerKey))
+	return hex.EncodeToString(hasher.Sum(nil))
+}
+
+func (s *Server) fault(method, path string) *Fault {
+	for i, fault := range s.faults {
+		if (fault.Method == "" || fault.Method == method) && (fault.Path == "" || fault.Path == path) {
+			if fault.Times > 0 {
+				fault.Times--
+				if fault.Times == 0 {
+					s.faults = append(s.faults[:i], s.faults[i+1:]...)
+				}
+			}
+			return fault
+		}
+	}
+	return nil
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.offset)
+}
+
+func (s *Server) createJob(body []byte) (interface{}, error) {
+	var newJob elementalconductor.Job
+	err := xml.Unmarshal(body, &newJob)
+	if err != nil {
+		return nil, unprocessable("invalid job: " + err.Error())
+	}
+	if len(newJob.Input) == 0 {
+		return nil, unprocessable("job must have at least one input")
+	}
+	s.lastJobID++
+	newJob.Href = "/jobs/" + strconv.Itoa(s.lastJobID)
+	j := job{job: newJob, submitted: s.now()}
+	var servers []string
+	for _, node := range s.nodes {
+		if node.Product == elementalconductor.ProductServer && node.Status.Healthy() {
+			servers = append(servers, node.GetID())
+		}
+	}
+	if len(servers) > 0 {
+		j.nodeID = servers[(s.lastJobID-1)%len(servers)]
+	}
+	s.jobs[s.lastJobID] = &j
+	return s.render(&j), nil
+}
+
+func (s *Server) findJob(jobID string) *job {
+	id, err := strconv.Atoi(jobID)
+	if err != nil {
+		return nil
+	}
+	return s.jobs[id]
+}
+
+func (s *Server) getJob(jobID string) (interface{}, error) {
+	j := s.findJob(jobID)
+	if j == nil {
+		return nil, notFound("job", jobID)
+	}
+	return s.render(j), nil
+}
+
+func (s *Server) cancelJob(jobID string) (interface{}, error) {
+	j := s.findJob(jobID)
+	if j == nil {
+		return nil, notFound("job", jobID)
+	}
+	if !s.stop(j, elementalconductor.JobStatusCancelled) {
+		return nil, unprocessable("job " + jobID + " can't be cancelled")
+	}
+	return s.render(j), nil
+}
+
+func (s *Server) stop(j *job, status elementalconductor.JobStatus) bool {
+	if current, _ := s.progress(j); current.IsTerminal() {
+		return false
+	}
+	j.stoppedAt = s.now()
+	j.status = status
+	return true
+}
+
+// progress returns the status and the percentage of completion of the given
+// job, based on the time since it was submitted. The first 10% of the
+// processing time is spent in the Pending status, followed by 10% in
+// Preprocessing, 70% in Running and 10% in Postprocessing. Jobs are complete
+// from the start when the processing time is zero.
+func (s *Server) progress(j *job) (elementalconductor.JobStatus, int) {
+	until := s.now()
+	if j.status != "" {
+		until = j.stoppedAt
+	}
+	pct := 100
+	if s.processingTime > 0 {
+		pct = int(until.Sub(j.submitted) * 100 / s.processingTime)
+	}
+	if pct > 100 {
+		pct = 100
+	}
+	if j.status != "" {
+		return j.status, pct
+	}
+	switch {
+	case pct < 10:
+		return elementalconductor.JobStatusPending, pct
+	case pct < 20:
+		return elementalconductor.JobStatusPreprocessing, pct
+	case pct < 90:
+		return elementalconductor.JobStatusRunning, pct
+	case pct < 100:
+		return elementalconductor.JobStatusPostprocessing, pct
+	}
+	return elementalconductor.JobStatusComplete, pct
+}
+
+func (s *Server) render(j *job) elementalconductor.Job {
+	result := j.job
+	status, pct := s.progress(j)
+	result.Status = status
+	result.PercentComplete = pct
+	result.Submitted = dateTime(j.submitted)
+	if status != elementalconductor.JobStatusPending {
+		result.StartTime = dateTime(j.submitted.Add(s.processingTime / 10))
+	}
+	switch status {
+	case elementalconductor.JobStatusComplete:
+		result.CompleteTime = dateTime(j.submitted.Add(s.processingTime))
+	case elementalconductor.JobStatusError:
+		result.ErroredTime = dateTime(j.stoppedAt)
+		result.ErrorMessages = []elementalconductor.JobError{
+			{
+				Code:      1040,
+				CreatedAt: elementalconductor.JobErrorDateTime{Time: j.stoppedAt.UTC().Truncate(time.Second)},
+				Message:   j.errorMessage,
+			},
+		}
+	}
+	return result
+}
+
+func dateTime(t time.Time) elementalconductor.DateTime {
+	return elementalconductor.DateTime{Time: t.UTC().Truncate(time.Second)}
+}
+
+func (s *Server) jobList(filter func(*job) bool) elementalconductor.JobList {
+	var ids []int
+	for id, j := range s.jobs {
+		if filter(j) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	var list elementalconductor.JobList
+	for _, id := range ids {
+		list.Job = append(list.Job, s.render(s.jobs[id]))
+	}
+	if len(list.Job) == 0 {
+		list.Empty = "There are currently no jobs"
+	}
+	return list
+}
+
+type presetList struct {
+	XMLName xml.Name                    `xml:"preset_list"`
+	Presets []elementalconductor.Preset `xml:"preset"`
+}
+
+func (s *Server) addPreset(preset elementalconductor.Preset) elementalconductor.Preset {
+	s.lastPresetID++
+	preset.Href = "/presets/" + strconv.Itoa(s.lastPresetID)
+	s.presets[s.lastPresetID] = preset
+	return preset
+}
+
+func (s *Server) presetList() presetList {
+	var ids []int
+	for id := range s.presets {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	var list presetList
+	for _, id := range ids {
+		list.Presets = append(list.Presets, s.presets[id])
+	}
+	return list
+}
+
+func (s *Server) createPreset(body []byte) (interface{}, error) {
+	preset, err := s.parsePreset(body, 0)
+	if err != nil {
+		return nil, err
+	}
+	return s.addPreset(preset), nil
+}
+
+func (s *Server) updatePreset(presetID string, body []byte) (interface{}, error) {
+	id, ok := s.findPreset(presetID)
+	if !ok {
+		return nil, notFound("preset", presetID)
+	}
+	preset, err := s.parsePreset(body, id)
+	if err != nil {
+		return nil, err
+	}
+	preset.Href = s.presets[id].Href
+	s.presets[id] = preset
+	return preset, nil
+}
+
+// parsePreset decodes and validates the given preset, ensuring its name isn't
+// taken by any preset other than the one with the given id.
+func (s *Server) parsePreset(body []byte, id int) (elementalconductor.Preset, error) {
+	var preset elementalconductor.Preset
+	err := xml.Unmarshal(body, &preset)
+	if err != nil {
+		return preset, unprocessable("invalid preset: " + err.Error())
+	}
+	if preset.Name == "" {
+		return preset, unprocessable("preset name can't be blank")
+	}
+	for existingID, existing := range s.presets {
+		if existingID != id && existing.Name == preset.Name {
+			return preset, unprocessable("preset name has already been taken")
+		}
+	}
+	return preset, nil
+}
+
+func (s *Server) findPreset(presetID string) (int, bool) {
+	id, err := strconv.Atoi(presetID)
+	if err != nil {
+		return 0, false
+	}
+	_, ok := s.presets[id]
+	return id, ok
+}
+
+func (s *Server) getPreset(presetID string) (interface{}, error) {
+	id, ok := s.findPreset(presetID)
+	if !ok {
+		return nil, notFound("preset", presetID)
+	}
+	return s.presets[id], nil
+}
+
+func (s *Server) deletePreset(presetID string) error {
+	id, ok := s.findPreset(presetID)
+	if !ok {
+		return notFound("preset", presetID)
+	}
+	delete(s.presets, id)
+	return nil
+}
+
+type nodeList struct {
+	XMLName xml.Name                  `xml:"node_list"`
+	Nodes   []elementalconductor.Node `xml:"node"`
+}
+
+func (s *Server) findNode(nodeID string) *elementalconductor.Node {
+	for i := range s.nodes {
+		if s.nodes[i].GetID() == nodeID {
+			return &s.nodes[i]
+		}
+	}
+	return nil
+}
+
+func (s *Server) getNode(nodeID string) (interface{}, error) {
+	node := s.findNode(nodeID)
+	if node == nil {
+		return nil, notFound("node", nodeID)
+	}
+	result := *node
+	result.RunningCount = 0
+	for _, j := range s.jobs {
+		if status, _ := s.progress(j); j.nodeID == nodeID && status.IsActive() {
+			result.RunningCount++
+		}
+	}
+	return result, nil
+}
+
+func (s *Server) getNodeJobs(nodeID string) (interface{}, error) {
+	if s.findNode(nodeID) == nil {
+		return nil, notFound("node", nodeID)
+	}
+	return s.jobList(func(j *job) bool {
+		status, _ := s.progress(j)
+		return j.nodeID == nodeID && status.IsActive()
+	}), nil
+}
+
+func (s *Server) nodeAction(nodeID, action string) (interface{}, error) {
+	node := s.findNode(nodeID)
+	if node == nil {
+		return nil, notFound("node", nodeID)
+	}
+	event := elementalconductor.NodeEvent{CreatedAt: dateTime(s.now())}
+	switch action {
+	case "enable":
+		node.Status = elementalconductor.NodeStatusIdle
+		event.Message = "Node enabled"
+	case "disable":
+		node.Status = elementalconductor.NodeStatusOffline
+		event.Message = "Node disabled"
+	case "reboot":
+		event.Message = "Node rebooted"
+	default:
+		return nil, &httpError{status: http.StatusNotFound, message: "not found"}
+	}
+	event.Status = node.Status
+	node.StatusHistory = append(node.StatusHistory, event)
+	return *node, nil
+}
+
+func (s *Server) updateCloudConfig(body []byte) (interface{}, error) {
+	var config elementalconductor.CloudConfig
+	err := xml.Unmarshal(body, &config)
+	if err != nil {
+		return nil, unprocessable("invalid cloud config: " + err.Error())
+	}
+	config.AuthorizedNodeCount = s.cloudConfig.AuthorizedNodeCount
+	if err := config.Validate(); err != nil {
+		return nil, unprocessable(err.Error())
+	}
+	s.cloudConfig = config
+	return s.cloudConfig, nil
+}
@@ -0,0 +1,334 @@
+package elementalconductortest
+
+import (
+	// #nosec
+	"crypto/md5"
+	"encoding/hex"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/NYTimes/encoding-wrapper/elementalconductor"
+)
+
+func createJob(t *testing.T, client *elementalconductor.Client) *elementalconductor.Job {
+	job, err := client.CreateJob(&elementalconductor.Job{
+		Input: []elementalconductor.Input{
+			{FileInput: elementalconductor.Location{URI: "s3://mybucket/source.mov"}},
+		},
+		StreamAssembly: []elementalconductor.StreamAssembly{
+			{Name: "stream_1", Preset: "mp4_1080p"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return job
+}
+
+func TestJobProgression(t *testing.T) {
+	server := NewServer("myuser", "secret-key")
+	defer server.Close()
+	server.SetProcessingTime(100 * time.Minute)
+	client := server.Client()
+	job := createJob(t, client)
+	if job.GetID() != "1" {
+		t.Errorf("wrong job id\nwant %q\ngot  %q", "1", job.GetID())
+	}
+	if job.Status != elementalconductor.JobStatusPending {
+		t.Errorf("wrong status\nwant %q\ngot  %q", elementalconductor.JobStatusPending, job.Status)
+	}
+
+	var tests = []struct {
+		advance time.Duration
+		status  elementalconductor.JobStatus
+		pct     int
+	}{
+		{15 * time.Minute, elementalconductor.JobStatusPreprocessing, 15},
+		{35 * time.Minute, elementalconductor.JobStatusRunning, 50},
+		{45 * time.Minute, elementalconductor.JobStatusPostprocessing, 95},
+		{time.Hour, elementalconductor.JobStatusComplete, 100},
+	}
+	for _, test := range tests {
+		server.Advance(test.advance)
+		job, err := client.GetJob("1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if job.Status != test.status {
+			t.Errorf("wrong status\nwant %q\ngot  %q", test.status, job.Status)
+		}
+		if job.PercentComplete != test.pct {
+			t.Errorf("wrong pct_complete\nwant %d\ngot  %d", test.pct, job.PercentComplete)
+		}
+	}
+	if job, _ := client.GetJob("1"); job.CompleteTime.Sub(job.Submitted.Time) != 100*time.Minute {
+		t.Errorf("wrong complete time: %s (submitted at %s)", job.CompleteTime, job.Submitted)
+	}
+	if _, err := client.CancelJob("1"); err == nil {
+		t.Error("unexpected <nil> error when cancelling complete job")
+	}
+}
+
+func TestJobZeroProcessingTime(t *testing.T) {
+	for _, d := range []time.Duration{0, -time.Minute} {
+		d := d
+		t.Run(d.String(), func(t *testing.T) {
+			server := NewServer("myuser", "secret-key")
+			defer server.Close()
+			server.SetProcessingTime(d)
+			job := createJob(t, server.Client())
+			if job.Status != elementalconductor.JobStatusComplete {
+				t.Errorf("wrong status\nwant %q\ngot  %q", elementalconductor.JobStatusComplete, job.Status)
+			}
+			if job.PercentComplete != 100 {
+				t.Errorf("wrong pct_complete\nwant 100\ngot  %d", job.PercentComplete)
+			}
+			if !job.CompleteTime.Equal(job.Submitted.Time) {
+				t.Errorf("wrong complete time: %s (submitted at %s)", job.CompleteTime, job.Submitted)
+			}
+		})
+	}
+}
+
+func TestCancelJob(t *testing.T) {
+	server := NewServer("myuser", "secret-key")
+	defer server.Close()
+	client := server.Client()
+	createJob(t, client)
+	job, err := client.CancelJob("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != elementalconductor.JobStatusCancelled {
+		t.Errorf("wrong status\nwant %q\ngot  %q", elementalconductor.JobStatusCancelled, job.Status)
+	}
+	server.Advance(time.Hour)
+	job, _ = client.GetJob("1")
+	if job.Status != elementalconductor.JobStatusCancelled {
+		t.Errorf("wrong status after advancing\nwant %q\ngot  %q", elementalconductor.JobStatusCancelled, job.Status)
+	}
+	_, err = client.GetJob("404")
+	if apiErr, ok := err.(*elementalconductor.APIError); !ok || apiErr.Status != http.StatusNotFound {
+		t.Errorf("wrong error for unknown job: %#v", err)
+	}
+}
+
+func TestFailJob(t *testing.T) {
+	server := NewServer("myuser", "secret-key")
+	defer server.Close()
+	client := server.Client()
+	createJob(t, client)
+	if !server.FailJob("1", "failed to download the input") {
+		t.Fatal("unexpected false return from FailJob")
+	}
+	job, err := client.GetJob("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != elementalconductor.JobStatusError {
+		t.Errorf("wrong status\nwant %q\ngot  %q", elementalconductor.JobStatusError, job.Status)
+	}
+	if len(job.ErrorMessages) != 1 || job.ErrorMessages[0].Message != "failed to download the input" {
+		t.Errorf("wrong error messages: %#v", job.ErrorMessages)
+	}
+	if server.FailJob("1", "again") {
+		t.Error("unexpected true return from FailJob for failed job")
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	server := NewServer("myuser", "secret-key")
+	defer server.Close()
+	var tests = []struct {
+		name   string
+		client *elementalconductor.Client
+	}{
+		{"wrong key", elementalconductor.NewClient(server.URL, "myuser", "wrong-key", 45, "", "", "")},
+		{"wrong user", elementalconductor.NewClient(server.URL, "otheruser", "secret-key", 45, "", "", "")},
+		{"expired", elementalconductor.NewClient(server.URL, "myuser", "secret-key", -45, "", "", "")},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			_, err := test.client.GetJobs()
+			if apiErr, ok := err.(*elementalconductor.APIError); !ok || apiErr.Status != http.StatusUnauthorized {
+				t.Errorf("wrong error\nwant 401 APIError\ngot  %#v", err)
+			}
+		})
+	}
+	if _, err := server.Client().GetJobs(); err != nil {
+		t.Errorf("unexpected error with valid credentials: %s", err)
+	}
+}
+
+func TestInjectFault(t *testing.T) {
+	server := NewServer("myuser", "secret-key")
+	defer server.Close()
+	client := server.Client()
+	server.InjectFault(Fault{Method: "GET", Path: "/nodes", StatusCode: http.StatusServiceUnavailable, Body: "<errors><error>unavailable</error></errors>", Times: 1})
+	_, err := client.GetNodes()
+	expectedErr := &elementalconductor.APIError{Status: http.StatusServiceUnavailable, Errors: "<errors><error>unavailable</error></errors>"}
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("wrong error\nwant %#v\ngot  %#v", expectedErr, err)
+	}
+	nodes, err := client.GetNodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(nodes) != 3 {
+		t.Errorf("wrong number of nodes\nwant 3\ngot  %d", len(nodes))
+	}
+
+	server.InjectFault(Fault{StatusCode: http.StatusInternalServerError})
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetPresets(); err == nil {
+			t.Error("unexpected <nil> error")
+		}
+	}
+	server.ClearFaults()
+	if _, err := client.GetPresets(); err != nil {
+		t.Errorf("unexpected error after clearing faults: %s", err)
+	}
+}
+
+func TestPresets(t *testing.T) {
+	server := NewServer("myuser", "secret-key")
+	defer server.Close()
+	client := server.Client()
+	created, err := client.CreatePreset(&elementalconductor.Preset{
+		Name:       "mp4_1080p",
+		Container:  "mp4",
+		Width:      "1920",
+		Height:     "1080",
+		VideoCodec: "h.264",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Href != "/presets/1" {
+		t.Errorf("wrong href\nwant %q\ngot  %q", "/presets/1", created.Href)
+	}
+	if _, err = client.CreatePreset(&elementalconductor.Preset{Name: "mp4_1080p"}); err == nil {
+		t.Error("unexpected <nil> error when creating duplicate preset")
+	}
+	preset, err := client.GetPreset("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(preset, created) {
+		t.Errorf("wrong preset\nwant %#v\ngot  %#v", created, preset)
+	}
+	list, err := client.GetPresets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Presets) != 1 {
+		t.Errorf("wrong number of presets\nwant 1\ngot  %d", len(list.Presets))
+	}
+	preset.VideoBitrate = "5000000"
+	updated, err := client.UpdatePreset("1", preset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Href != "/presets/1" || updated.VideoBitrate != "5000000" {
+		t.Errorf("wrong updated preset: %#v", updated)
+	}
+	other, err := client.CreatePreset(&elementalconductor.Preset{Name: "webm_720p"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.UpdatePreset("1", other); err == nil {
+		t.Error("unexpected <nil> error when renaming preset to a taken name")
+	}
+	if err = client.DeletePreset("1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = client.GetPreset("1"); err == nil {
+		t.Error("unexpected <nil> error when getting deleted preset")
+	}
+}
+
+func TestNodes(t *testing.T) {
+	server := NewServer("myuser", "secret-key")
+	defer server.Close()
+	client := server.Client()
+	createJob(t, client)
+	server.Advance(5 * time.Second)
+
+	node, err := client.GetNode("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.RunningCount != 1 {
+		t.Errorf("wrong running count\nwant 1\ngot  %d", node.RunningCount)
+	}
+	jobs, err := client.GetNodeJobs("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs.Job) != 1 || jobs.Job[0].GetID() != "1" {
+		t.Errorf("wrong node jobs: %#v", jobs.Job)
+	}
+
+	node, err = client.DisableNode("3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Status != elementalconductor.NodeStatusOffline || len(node.StatusHistory) != 1 {
+		t.Errorf("wrong node after disabling: %#v", node)
+	}
+	health, err := client.GetClusterHealth()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if health.HealthyNodes != 2 {
+		t.Errorf("wrong number of healthy nodes\nwant 2\ngot  %d", health.HealthyNodes)
+	}
+	node, err = client.EnableNode("3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.Status != elementalconductor.NodeStatusIdle || len(node.StatusHistory) != 2 {
+		t.Errorf("wrong node after enabling: %#v", node)
+	}
+}
+
+func TestCloudConfig(t *testing.T) {
+	server := NewServer("myuser", "secret-key")
+	defer server.Close()
+	client := server.Client()
+	config, err := client.GetCloudConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.MinNodes = 2
+	config.MaxNodes = 8
+	updated, err := client.UpdateCloudConfig(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.MinNodes != 2 || updated.MaxNodes != 8 || updated.AuthorizedNodeCount != 10 {
+		t.Errorf("wrong config: %#v", updated)
+	}
+	config.AuthorizedNodeCount = 0
+	config.MaxNodes = 20
+	_, err = client.UpdateCloudConfig(config)
+	if apiErr, ok := err.(*elementalconductor.APIError); !ok || apiErr.Status != http.StatusUnprocessableEntity {
+		t.Errorf("wrong error\nwant 422 APIError\ngot  %#v", err)
+	}
+}
+
+func TestAuthKey(t *testing.T) {
+	// #nosec
+	innerKeyMD5 := md5.Sum([]byte("/jobs" + "myuser" + "api-key" + "1"))
+	innerKey := hex.EncodeToString(innerKeyMD5[:])
+	// #nosec
+	value := md5.Sum([]byte("api-key" + innerKey))
+	expected := hex.EncodeToString(value[:])
+	got := AuthKey("/jobs", "myuser", "api-key", "1")
+	if got != expected {
+		t.Errorf("wrong auth key returned\nwant %q\ngot  %q", expected, got)
+	}
+}
@@ -97,12 +97,12 @@ type JobList struct {
 type Job struct {
 	XMLName         xml.Name         `xml:"job"`
 	Href            string           `xml:"href,attr,omitempty"`
-	Input           Input            `xml:"input,omitempty"`
+	Input           []Input          `xml:"input,omitempty"`
 	ContentDuration *ContentDuration `xml:"content_duration,omitempty"`
 	Priority        int              `xml:"priority,omitempty"`
 	OutputGroup     []OutputGroup    `xml:"output_group,omitempty"`
 	StreamAssembly  []StreamAssembly `xml:"stream_assembly,omitempty"`
-	Status          string           `xml:"status,omitempty"`
+	Status          JobStatus        `xml:"status,omitempty"`
 	Submitted       DateTime         `xml:"submitted,omitempty"`
 	StartTime       DateTime         `xml:"start_time,omitempty"`
 	CompleteTime    DateTime         `xml:"complete_time,omitempty"`
@@ -118,10 +118,38 @@ type JobError struct {
 	Message   string           `xml:"error>message,omitempty"`
 }
 
-// Input represents the spec for the job's input
+// TimecodeSource is the source of the timecode used for an input.
+type TimecodeSource string
+
+const (
+	// EmbeddedTimecodeSource uses the timecode embedded in the input.
+	EmbeddedTimecodeSource = TimecodeSource("embedded")
+	// ZeroBasedTimecodeSource starts the timecode of the input at
+	// 00:00:00:00.
+	ZeroBasedTimecodeSource = TimecodeSource("zerobased")
+	// SpecifiedStartTimecodeSource starts the timecode of the input at the
+	// value defined in the TimecodeStart field of Input.
+	SpecifiedStartTimecodeSource = TimecodeSource("specifiedstart")
+)
+
+// Input represents the spec for the job's input. Jobs may have multiple
+// inputs, which are stitched together in the given Order.
 type Input struct {
-	FileInput Location   `xml:"file_input,omitempty"`
-	InputInfo *InputInfo `xml:"input_info,omitempty"`
+	Order           int               `xml:"order,omitempty"`
+	FileInput       Location          `xml:"file_input,omitempty"`
+	InputClipping   []InputClipping   `xml:"input_clipping,omitempty"`
+	TimecodeSource  TimecodeSource    `xml:"timecode_source,omitempty"`
+	TimecodeStart   string            `xml:"timecode_start,omitempty"`
+	CaptionSelector []CaptionSelector `xml:"caption_selector,omitempty"`
+	InputInfo       *InputInfo        `xml:"input_info,omitempty"`
+}
+
+// InputClipping defines a segment of the input that should be processed, in
+// the format HH:MM:SS:FF. The values are interpreted according to the
+// TimecodeSource of the Input.
+type InputClipping struct {
+	StartTimecode string `xml:"start_timecode,omitempty"`
+	EndTimecode   string `xml:"end_timecode,omitempty"`
 }
 
 // InputInfo contains metadata related to a job input.
@@ -178,7 +206,8 @@ func (v *VideoInputInfo) extractNumber(input string) int64 {
 // ContentDuration contains information about the content of the media in the
 // job.
 type ContentDuration struct {
-	InputDuration int `xml:"input_duration"`
+	InputDuration        int `xml:"input_duration"`
+	ClippedInputDuration int `xml:"clipped_input_duration,omitempty"`
 }
 
 // Location defines where a file is or needs to be.
@@ -214,29 +243,40 @@ type AppleLiveGroupSettings struct {
 // Output defines the different processing stream assemblies
 // for the job
 type Output struct {
-	FullURI            string    `xml:"full_uri,omitempty"`
-	StreamAssemblyName string    `xml:"stream_assembly_name,omitempty"`
-	NameModifier       string    `xml:"name_modifier,omitempty"`
-	Order              int       `xml:"order,omitempty"`
-	Extension          string    `xml:"extension,omitempty"`
-	Container          Container `xml:"container,omitempty"`
+	FullURI                 string    `xml:"full_uri,omitempty"`
+	StreamAssemblyName      string    `xml:"stream_assembly_name,omitempty"`
+	NameModifier            string    `xml:"name_modifier,omitempty"`
+	Order                   int       `xml:"order,omitempty"`
+	Extension               string    `xml:"extension,omitempty"`
+	Container               Container `xml:"container,omitempty"`
+	CaptionDescriptionNames []string  `xml:"caption_description_name,omitempty"`
 }
 
 // StreamAssembly defines how each processing stream should behave
 type StreamAssembly struct {
-	ID               string                  `xml:"id,omitempty"`
-	Name             string                  `xml:"name,omitempty"`
-	Preset           string                  `xml:"preset,omitempty"`
-	VideoDescription *StreamVideoDescription `xml:"video_description"`
+	ID                 string                  `xml:"id,omitempty"`
+	Name               string                  `xml:"name,omitempty"`
+	Preset             string                  `xml:"preset,omitempty"`
+	VideoDescription   *StreamVideoDescription `xml:"video_description"`
+	CaptionDescription []CaptionDescription    `xml:"caption_description,omitempty"`
 }
 
 // StreamVideoDescription contains information about the video in a given
 // stream assembly.
+//
+// AntiAlias and StretchToOutput control the scaling behavior when the output
+// resolution differs from the input. They're pointers so the Elemental
+// defaults are kept when they're not set.
 type StreamVideoDescription struct {
-	Codec       string `xml:"codec"`
-	EncoderType string `xml:"encoder_type"`
-	Height      string `xml:"height"`
-	Width       string `xml:"width"`
+	AntiAlias          *bool               `xml:"anti_alias,omitempty"`
+	Codec              string              `xml:"codec"`
+	EncoderType        string              `xml:"encoder_type"`
+	Height             string              `xml:"height"`
+	StretchToOutput    *bool               `xml:"stretch_to_output,omitempty"`
+	Width              string              `xml:"width"`
+	H264Settings       *H264Settings       `xml:"h264_settings,omitempty"`
+	H265Settings       *H265Settings       `xml:"h265_settings,omitempty"`
+	VideoPreprocessors *VideoPreprocessors `xml:"video_preprocessors,omitempty"`
 }
 
 // GetWidth returns the underlying width parsed as an int64.
@@ -0,0 +1,145 @@
+package elementalconductor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// JobStatus is the status of a job in the Elemental Conductor API.
+//
+// The API isn't consistent regarding the case and spelling of statuses, so
+// JobStatus values should be compared using the Is method instead of the
+// equality operator.
+type JobStatus string
+
+const (
+	// JobStatusPending is the status of jobs waiting for a node.
+	JobStatusPending = JobStatus("Pending")
+
+	// JobStatusPreprocessing is the status of jobs that are downloading and
+	// probing their inputs.
+	JobStatusPreprocessing = JobStatus("Preprocessing")
+
+	// JobStatusRunning is the status of jobs being encoded.
+	JobStatusRunning = JobStatus("Running")
+
+	// JobStatusPostprocessing is the status of jobs that are uploading
+	// their outputs.
+	JobStatusPostprocessing = JobStatus("Postprocessing")
+
+	// JobStatusComplete is the status of jobs that finished successfully.
+	JobStatusComplete = JobStatus("Complete")
+
+	// JobStatusCancelled is the status of jobs cancelled by the user.
+	JobStatusCancelled = JobStatus("Cancelled")
+
+	// JobStatusError is the status of jobs that failed.
+	JobStatusError = JobStatus("Error")
+
+	// JobStatusArchived is the status of finished jobs that were archived.
+	JobStatusArchived = JobStatus("Archived")
+)
+
+var jobStatusTransitions = map[JobStatus][]JobStatus{
+	JobStatusPending:        {JobStatusPreprocessing, JobStatusRunning, JobStatusCancelled, JobStatusError},
+	JobStatusPreprocessing:  {JobStatusRunning, JobStatusCancelled, JobStatusError},
+	JobStatusRunning:        {JobStatusPostprocessing, JobStatusComplete, JobStatusCancelled, JobStatusError},
+	JobStatusPostprocessing: {JobStatusComplete, JobStatusCancelled, JobStatusError},
+	JobStatusComplete:       {JobStatusArchived},
+	JobStatusCancelled:      {JobStatusArchived},
+	JobStatusError:          {JobStatusArchived},
+	JobStatusArchived:       {},
+}
+
+// Normalize returns the known JobStatus matching the given status, ignoring
+// case and accepting both "canceled" and "cancelled". Unknown statuses are
+// returned unchanged.
+func (s JobStatus) Normalize() JobStatus {
+	value := strings.ToLower(strings.TrimSpace(string(s)))
+	if value == "canceled" {
+		value = "cancelled"
+	}
+	for status := range jobStatusTransitions {
+		if strings.ToLower(string(status)) == value {
+			return status
+		}
+	}
+	return s
+}
+
+// Is returns whether the status matches the given status, ignoring
+// differences in case and spelling.
+func (s JobStatus) Is(other JobStatus) bool {
+	return s.Normalize() == other.Normalize()
+}
+
+// Known returns whether the status is one of the statuses known by this
+// package.
+func (s JobStatus) Known() bool {
+	_, ok := jobStatusTransitions[s.Normalize()]
+	return ok
+}
+
+// IsTerminal returns whether the status represents a job that won't make any
+// progress anymore (complete, cancelled, errored or archived). Terminal jobs
+// can only move to JobStatusArchived.
+func (s JobStatus) IsTerminal() bool {
+	switch s.Normalize() {
+	case JobStatusComplete, JobStatusCancelled, JobStatusError, JobStatusArchived:
+		return true
+	}
+	return false
+}
+
+// IsActive returns whether the status represents a job that is currently
+// being processed by a node.
+func (s JobStatus) IsActive() bool {
+	switch s.Normalize() {
+	case JobStatusPreprocessing, JobStatusRunning, JobStatusPostprocessing:
+		return true
+	}
+	return false
+}
+
+// CanTransitionTo returns whether a job in the status s can legally move to
+// the status next. Staying in the same status is always legal, and
+// transitions from or to unknown statuses are never legal.
+func (s JobStatus) CanTransitionTo(next JobStatus) bool {
+	from, to := s.Normalize(), next.Normalize()
+	allowed, ok := jobStatusTransitions[from]
+	if !ok || !to.Known() {
+		return false
+	}
+	if from == to {
+		return true
+	}
+	for _, status := range allowed {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition returns an error of type *JobStatusTransitionError when
+// a job can't legally move from the status s to the status next, or nil
+// otherwise.
+func (s JobStatus) ValidateTransition(next JobStatus) error {
+	if !s.CanTransitionTo(next) {
+		return &JobStatusTransitionError{From: s, To: next}
+	}
+	return nil
+}
+
+// JobStatusTransitionError is the error returned when a job moves between
+// two statuses in a way that isn't allowed, which usually means that a
+// poller is looking at stale data.
+type JobStatusTransitionError struct {
+	From JobStatus
+	To   JobStatus
+}
+
+// Error returns a representative message describing the transition.
+func (err *JobStatusTransitionError) Error() string {
+	return fmt.Sprintf("invalid job status transition from %q to %q", err.From, err.To)
+}
@@ -0,0 +1,122 @@
+package elementalconductor
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJobStatusNormalize(t *testing.T) {
+	var tests = []struct {
+		input    JobStatus
+		expected JobStatus
+	}{
+		{"Complete", JobStatusComplete},
+		{"complete", JobStatusComplete},
+		{"RUNNING", JobStatusRunning},
+		{"canceled", JobStatusCancelled},
+		{"Cancelled", JobStatusCancelled},
+		{" error ", JobStatusError},
+		{"suspended", "suspended"},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(string(test.input), func(t *testing.T) {
+			if got := test.input.Normalize(); got != test.expected {
+				t.Errorf("wrong status\nwant %q\ngot  %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestJobStatusIs(t *testing.T) {
+	if !JobStatus("canceled").Is(JobStatusCancelled) {
+		t.Error("expected canceled to match JobStatusCancelled")
+	}
+	if JobStatus("running").Is(JobStatusComplete) {
+		t.Error("unexpected match between running and JobStatusComplete")
+	}
+}
+
+func TestJobStatusHelpers(t *testing.T) {
+	var tests = []struct {
+		status   JobStatus
+		terminal bool
+		active   bool
+		known    bool
+	}{
+		{JobStatusPending, false, false, true},
+		{JobStatusPreprocessing, false, true, true},
+		{JobStatusRunning, false, true, true},
+		{JobStatusPostprocessing, false, true, true},
+		{JobStatusComplete, true, false, true},
+		{JobStatusCancelled, true, false, true},
+		{JobStatusError, true, false, true},
+		{JobStatusArchived, true, false, true},
+		{"canceled", true, false, true},
+		{"whatever", false, false, false},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(string(test.status), func(t *testing.T) {
+			if got := test.status.IsTerminal(); got != test.terminal {
+				t.Errorf("wrong IsTerminal\nwant %v\ngot  %v", test.terminal, got)
+			}
+			if got := test.status.IsActive(); got != test.active {
+				t.Errorf("wrong IsActive\nwant %v\ngot  %v", test.active, got)
+			}
+			if got := test.status.Known(); got != test.known {
+				t.Errorf("wrong Known\nwant %v\ngot  %v", test.known, got)
+			}
+		})
+	}
+}
+
+func TestJobStatusTransitions(t *testing.T) {
+	var tests = []struct {
+		from  JobStatus
+		to    JobStatus
+		legal bool
+	}{
+		{JobStatusPending, JobStatusRunning, true},
+		{JobStatusPending, JobStatusPending, true},
+		{JobStatusRunning, JobStatusPostprocessing, true},
+		{"running", "Complete", true},
+		{JobStatusRunning, "canceled", true},
+		{JobStatusComplete, JobStatusArchived, true},
+		{JobStatusError, JobStatusArchived, true},
+		{JobStatusError, JobStatusPending, false},
+		{JobStatusCancelled, JobStatusPending, false},
+		{JobStatusRunning, JobStatusPending, false},
+		{JobStatusComplete, JobStatusRunning, false},
+		{JobStatusPostprocessing, JobStatusPreprocessing, false},
+		{JobStatusArchived, JobStatusComplete, false},
+		{JobStatusRunning, "whatever", false},
+		{"whatever", JobStatusRunning, false},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(string(test.from)+"->"+string(test.to), func(t *testing.T) {
+			if got := test.from.CanTransitionTo(test.to); got != test.legal {
+				t.Errorf("wrong CanTransitionTo\nwant %v\ngot  %v", test.legal, got)
+			}
+			err := test.from.ValidateTransition(test.to)
+			if test.legal && err != nil {
+				t.Errorf("unexpected error: %s", err)
+			}
+			if !test.legal {
+				expectedErr := &JobStatusTransitionError{From: test.from, To: test.to}
+				if !reflect.DeepEqual(err, expectedErr) {
+					t.Errorf("wrong error\nwant %#v\ngot  %#v", expectedErr, err)
+				}
+			}
+		})
+	}
+}
+
+func TestJobStatusTransitionErrorMessage(t *testing.T) {
+	err := &JobStatusTransitionError{From: JobStatusComplete, To: JobStatusRunning}
+	expected := `invalid job status transition from "Complete" to "Running"`
+	if err.Error() != expected {
+		t.Errorf("wrong error message\nwant %q\ngot  %q", expected, err.Error())
+	}
+}
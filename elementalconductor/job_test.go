@@ -97,11 +97,13 @@ func TestCreateJob(t *testing.T) {
 			Local: "job",
 		},
 		Href: "/jobs/1",
-		Input: Input{
-			FileInput: Location{
-				URI:      "http://another.non.existent/video.mp4",
-				Username: "user",
-				Password: "pass123",
+		Input: []Input{
+			{
+				FileInput: Location{
+					URI:      "http://another.non.existent/video.mp4",
+					Username: "user",
+					Password: "pass123",
+				},
 			},
 		},
 		Priority: 50,
@@ -151,6 +153,89 @@ func TestCreateJob(t *testing.T) {
 	}
 }
 
+func TestCreateJobMultipleInputs(t *testing.T) {
+	jobResponseXML := `<job href="/jobs/1">
+    <input>
+        <order>1</order>
+        <file_input>
+            <uri>http://another.non.existent/bumper.mp4</uri>
+        </file_input>
+        <timecode_source>zerobased</timecode_source>
+    </input>
+    <input>
+        <order>2</order>
+        <file_input>
+            <uri>http://another.non.existent/video.mp4</uri>
+        </file_input>
+        <input_clipping>
+            <start_timecode>00:00:10:00</start_timecode>
+            <end_timecode>00:01:30:00</end_timecode>
+        </input_clipping>
+        <timecode_source>specifiedstart</timecode_source>
+        <timecode_start>01:00:00:00</timecode_start>
+    </input>
+    <content_duration>
+        <input_duration>716</input_duration>
+        <clipped_input_duration>95</clipped_input_duration>
+    </content_duration>
+    <stream_assembly>
+        <name>stream_1</name>
+        <preset>17</preset>
+    </stream_assembly>
+</job>`
+	server, requests := startServer(http.StatusCreated, jobResponseXML)
+	defer server.Close()
+	jobInput := Job{
+		XMLName: xml.Name{
+			Local: "job",
+		},
+		Input: []Input{
+			{
+				Order:          1,
+				FileInput:      Location{URI: "http://another.non.existent/bumper.mp4"},
+				TimecodeSource: ZeroBasedTimecodeSource,
+			},
+			{
+				Order:     2,
+				FileInput: Location{URI: "http://another.non.existent/video.mp4"},
+				InputClipping: []InputClipping{
+					{StartTimecode: "00:00:10:00", EndTimecode: "00:01:30:00"},
+				},
+				TimecodeSource: SpecifiedStartTimecodeSource,
+				TimecodeStart:  "01:00:00:00",
+			},
+		},
+		StreamAssembly: []StreamAssembly{
+			{
+				Name:   "stream_1",
+				Preset: "17",
+			},
+		},
+	}
+	client := NewClient(server.URL, "myuser", "secret-key", 45, "aws-access-key", "aws-secret-key", "destination")
+
+	postJobResponse, err := client.CreateJob(&jobInput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedJob := jobInput
+	expectedJob.Href = "/jobs/1"
+	expectedJob.ContentDuration = &ContentDuration{InputDuration: 716, ClippedInputDuration: 95}
+	if !reflect.DeepEqual(*postJobResponse, expectedJob) {
+		t.Errorf("wrong response\nwant %#v\ngot  %#v", expectedJob, *postJobResponse)
+	}
+
+	fakeReq := <-requests
+	var sentJob Job
+	err = xml.Unmarshal(fakeReq.body, &sentJob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sentJob, jobInput) {
+		t.Errorf("wrong job sent\nwant %#v\ngot  %#v", jobInput, sentJob)
+	}
+}
+
 func TestGetJob(t *testing.T) {
 	jobResponseXML := `<job href="/jobs/1">
     <input>
@@ -250,31 +335,34 @@ func TestGetJob(t *testing.T) {
 </job>`
 	server, _ := startServer(http.StatusOK, jobResponseXML)
 	defer server.Close()
+	antiAlias, stretchToOutput := true, false
 	expectedJob := Job{
 		XMLName: xml.Name{
 			Local: "job",
 		},
 		Href: "/jobs/1",
-		Input: Input{
-			FileInput: Location{
-				URI:      "http://another.non.existent/video.mp4",
-				Username: "user",
-				Password: "pass123",
-			},
-			InputInfo: &InputInfo{
-				Video: VideoInputInfo{
-					Bitrate:       "19.2 Mbps",
-					Format:        "AVC",
-					FormatInfo:    "Advanced Video Codec",
-					FormatProfile: "Main@L4.1",
-					CodecID:       "avc1",
-					CodecIDInfo:   "Advanced Video Coding",
-					Width:         "1 920 pixels",
-					Height:        "1 080 pixels",
+		Input: []Input{
+			{
+				FileInput: Location{
+					URI:      "http://another.non.existent/video.mp4",
+					Username: "user",
+					Password: "pass123",
+				},
+				InputInfo: &InputInfo{
+					Video: VideoInputInfo{
+						Bitrate:       "19.2 Mbps",
+						Format:        "AVC",
+						FormatInfo:    "Advanced Video Codec",
+						FormatProfile: "Main@L4.1",
+						CodecID:       "avc1",
+						CodecIDInfo:   "Advanced Video Coding",
+						Width:         "1 920 pixels",
+						Height:        "1 080 pixels",
+					},
 				},
 			},
 		},
-		ContentDuration: &ContentDuration{InputDuration: 716},
+		ContentDuration: &ContentDuration{InputDuration: 716, ClippedInputDuration: 716},
 		Priority:        50,
 		OutputGroup: []OutputGroup{
 			{
@@ -304,10 +392,12 @@ func TestGetJob(t *testing.T) {
 				Name:   "stream_1",
 				Preset: "17",
 				VideoDescription: &StreamVideoDescription{
-					Codec:       "h.264",
-					EncoderType: "gpu",
-					Width:       "",
-					Height:      "1080",
+					AntiAlias:       &antiAlias,
+					Codec:           "h.264",
+					EncoderType:     "gpu",
+					StretchToOutput: &stretchToOutput,
+					Width:           "",
+					Height:          "1080",
 				},
 			},
 		},
@@ -393,11 +483,13 @@ func TestCancelJob(t *testing.T) {
 			Local: "job",
 		},
 		Href: "/jobs/1",
-		Input: Input{
-			FileInput: Location{
-				URI:      "http://another.non.existent/video.mp4",
-				Username: "user",
-				Password: "pass123",
+		Input: []Input{
+			{
+				FileInput: Location{
+					URI:      "http://another.non.existent/video.mp4",
+					Username: "user",
+					Password: "pass123",
+				},
 			},
 		},
 		Priority: 50,
@@ -1,6 +1,9 @@
 package elementalconductor
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"strings"
+)
 
 // NodeProduct is the product that is running inside a node.
 type NodeProduct string
@@ -13,6 +16,44 @@ const (
 	ProductServer = NodeProduct("Server")
 )
 
+// NodeStatus is the status of a node.
+type NodeStatus string
+
+const (
+	// NodeStatusActive is the status of nodes that are up and able to run
+	// jobs.
+	NodeStatusActive = NodeStatus("active")
+
+	// NodeStatusIdle is the status of nodes that are up and not running any
+	// jobs.
+	NodeStatusIdle = NodeStatus("idle")
+
+	// NodeStatusBusy is the status of nodes that are running jobs at full
+	// capacity.
+	NodeStatusBusy = NodeStatus("busy")
+
+	// NodeStatusError is the status of nodes that are in an error state.
+	NodeStatusError = NodeStatus("error")
+
+	// NodeStatusOffline is the status of nodes that can't be reached by
+	// the Conductor.
+	NodeStatusOffline = NodeStatus("offline")
+
+	// NodeStatusProvisioning is the status of nodes that are still being
+	// started.
+	NodeStatusProvisioning = NodeStatus("provisioning")
+)
+
+// Healthy returns whether the status represents a node that is up and able to
+// do its work.
+func (s NodeStatus) Healthy() bool {
+	switch s {
+	case NodeStatusActive, NodeStatusIdle, NodeStatusBusy:
+		return true
+	}
+	return false
+}
+
 type nodeList struct {
 	XMLName xml.Name `xml:"node_list"`
 	Nodes   []Node   `xml:"node"`
@@ -26,7 +67,7 @@ type Node struct {
 	IPAddress       string      `xml:"ip_addr"`
 	PublicIPAddress string      `xml:"public_ip_addr,omitempty"`
 	Eth0Mac         string      `xml:"eth0_mac"`
-	Status          string      `xml:"status"`
+	Status          NodeStatus  `xml:"status"`
 	Product         NodeProduct `xml:"product"`
 	Version         string      `xml:"version"`
 	Platform        string      `xml:"platform"`
@@ -34,6 +75,16 @@ type Node struct {
 	Licenses        []string    `xml:"licenses>license"`
 	CreatedAt       DateTime    `xml:"created_at"`
 	RunningCount    int         `xml:"running_count,omitempty"`
+	CPULoad         float64     `xml:"cpu_load,omitempty"`
+	GPULoad         float64     `xml:"gpu_load,omitempty"`
+	StatusHistory   []NodeEvent `xml:"status_history>status_change,omitempty"`
+}
+
+// NodeEvent represents a change in the status of a node.
+type NodeEvent struct {
+	Status    NodeStatus `xml:"status"`
+	Message   string     `xml:"message,omitempty"`
+	CreatedAt DateTime   `xml:"created_at"`
 }
 
 // GetNodes returns the list of nodes currently available in the Elemental
@@ -46,3 +97,69 @@ func (c *Client) GetNodes() ([]Node, error) {
 	}
 	return result.Nodes, nil
 }
+
+// GetNode returns details about the given node, including its current load
+// and status history.
+func (c *Client) GetNode(nodeID string) (*Node, error) {
+	var result *Node
+	err := c.do("GET", "/nodes/"+nodeID, nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// EnableNode takes the given node out of maintenance, making it available
+// for running jobs again.
+func (c *Client) EnableNode(nodeID string) (*Node, error) {
+	var payload = struct {
+		XMLName xml.Name `xml:"enable"`
+	}{}
+	return c.doNodeAction(nodeID, "enable", payload)
+}
+
+// DisableNode puts the given node in maintenance. Jobs that are already
+// running on the node are not affected, but the node won't pick new jobs.
+func (c *Client) DisableNode(nodeID string) (*Node, error) {
+	var payload = struct {
+		XMLName xml.Name `xml:"disable"`
+	}{}
+	return c.doNodeAction(nodeID, "disable", payload)
+}
+
+// RebootNode reboots the given node.
+func (c *Client) RebootNode(nodeID string) (*Node, error) {
+	var payload = struct {
+		XMLName xml.Name `xml:"reboot"`
+	}{}
+	return c.doNodeAction(nodeID, "reboot", payload)
+}
+
+// GetNodeJobs returns the list of jobs currently running in the given node.
+func (c *Client) GetNodeJobs(nodeID string) (*JobList, error) {
+	var result *JobList
+	err := c.do("GET", "/nodes/"+nodeID+"/jobs", nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *Client) doNodeAction(nodeID string, action string, payload interface{}) (*Node, error) {
+	var result *Node
+	err := c.do("POST", "/nodes/"+nodeID+"/"+action, payload, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// GetID is a convenience function to parse the node id out of the Href
+// attribute in Node.
+func (n *Node) GetID() string {
+	if n.Href != "" {
+		hrefData := strings.Split(n.Href, "/")
+		return hrefData[len(hrefData)-1]
+	}
+	return ""
+}
@@ -1,6 +1,7 @@
 package elementalconductor
 
 import (
+	"encoding/xml"
 	"net/http"
 	"reflect"
 	"testing"
@@ -146,3 +147,190 @@ func TestGetNodes(t *testing.T) {
 		t.Errorf("wrong path used\nwant %q\ngot  %q", expectedPath, fakeReq.req.URL.Path)
 	}
 }
+
+func TestGetNode(t *testing.T) {
+	server, requests := startServer(http.StatusOK, `<?xml version="1.0" encoding="UTF-8"?>
+<node href="/nodes/31">
+  <name>Node 1</name>
+  <hostname>ip-192-168-1-141</hostname>
+  <ip_addr>192.168.1.141</ip_addr>
+  <eth0_mac>0E:C8:60:FA:3C:02</eth0_mac>
+  <status>busy</status>
+  <product>Server</product>
+  <version>1.0.0v123</version>
+  <platform>cloud</platform>
+  <created_at>2016-03-01 09:42:23 -0300</created_at>
+  <running_count>3</running_count>
+  <cpu_load>87.5</cpu_load>
+  <gpu_load>42.1</gpu_load>
+  <status_history>
+    <status_change>
+      <status>idle</status>
+      <created_at>2016-03-01 09:45:00 -0300</created_at>
+    </status_change>
+    <status_change>
+      <status>busy</status>
+      <message>Running 3 jobs</message>
+      <created_at>2016-03-01 10:00:00 -0300</created_at>
+    </status_change>
+  </status_history>
+</node>`)
+	defer server.Close()
+	client := NewClient(server.URL, "myuser", "secret-key", 45, "aws-access-key", "aws-secret-key", "destination")
+	node, err := client.GetNode("31")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedNode := Node{
+		Href:         "/nodes/31",
+		Name:         "Node 1",
+		HostName:     "ip-192-168-1-141",
+		IPAddress:    "192.168.1.141",
+		Eth0Mac:      "0E:C8:60:FA:3C:02",
+		Status:       "busy",
+		Product:      ProductServer,
+		Version:      "1.0.0v123",
+		Platform:     "cloud",
+		CreatedAt:    DateTime{Time: time.Date(2016, time.March, 1, 12, 42, 23, 0, time.UTC)},
+		RunningCount: 3,
+		CPULoad:      87.5,
+		GPULoad:      42.1,
+		StatusHistory: []NodeEvent{
+			{
+				Status:    "idle",
+				CreatedAt: DateTime{Time: time.Date(2016, time.March, 1, 12, 45, 0, 0, time.UTC)},
+			},
+			{
+				Status:    "busy",
+				Message:   "Running 3 jobs",
+				CreatedAt: DateTime{Time: time.Date(2016, time.March, 1, 13, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+	if !reflect.DeepEqual(*node, expectedNode) {
+		t.Errorf("wrong node returned\nwant %#v\ngot  %#v", expectedNode, *node)
+	}
+
+	fakeReq := <-requests
+	if expectedMethod := "GET"; fakeReq.req.Method != expectedMethod {
+		t.Errorf("wrong method used\nwant %q\ngot  %q", expectedMethod, fakeReq.req.Method)
+	}
+	if expectedPath := "/api/nodes/31"; fakeReq.req.URL.Path != expectedPath {
+		t.Errorf("wrong path used\nwant %q\ngot  %q", expectedPath, fakeReq.req.URL.Path)
+	}
+}
+
+func TestNodeActions(t *testing.T) {
+	var tests = []struct {
+		action      string
+		fn          func(*Client, string) (*Node, error)
+		expectedTag string
+	}{
+		{"enable", (*Client).EnableNode, "<enable></enable>"},
+		{"disable", (*Client).DisableNode, "<disable></disable>"},
+		{"reboot", (*Client).RebootNode, "<reboot></reboot>"},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.action, func(t *testing.T) {
+			server, requests := startServer(http.StatusOK, `<node href="/nodes/31"><name>Node 1</name><status>active</status></node>`)
+			defer server.Close()
+			client := NewClient(server.URL, "myuser", "secret-key", 45, "aws-access-key", "aws-secret-key", "destination")
+			node, err := test.fn(client, "31")
+			if err != nil {
+				t.Fatal(err)
+			}
+			expectedNode := Node{Href: "/nodes/31", Name: "Node 1", Status: "active"}
+			if !reflect.DeepEqual(*node, expectedNode) {
+				t.Errorf("wrong node returned\nwant %#v\ngot  %#v", expectedNode, *node)
+			}
+
+			fakeReq := <-requests
+			if fakeReq.req.Method != http.MethodPost {
+				t.Errorf("wrong method used\nwant %q\ngot  %q", http.MethodPost, fakeReq.req.Method)
+			}
+			if expectedPath := "/api/nodes/31/" + test.action; fakeReq.req.URL.Path != expectedPath {
+				t.Errorf("wrong path used\nwant %q\ngot  %q", expectedPath, fakeReq.req.URL.Path)
+			}
+			if string(fakeReq.body) != test.expectedTag {
+				t.Errorf("wrong body sent\nwant %q\ngot  %q", test.expectedTag, fakeReq.body)
+			}
+		})
+	}
+}
+
+func TestNodeActionError(t *testing.T) {
+	errorResponse := `<?xml version="1.0" encoding="UTF-8"?>
+<errors>
+  <error type="ActiveRecord::RecordNotFound">Couldn't find Node with id=31</error>
+</errors>`
+	server, _ := startServer(http.StatusNotFound, errorResponse)
+	defer server.Close()
+	client := NewClient(server.URL, "myuser", "secret-key", 45, "aws-access-key", "aws-secret-key", "destination")
+	node, err := client.DisableNode("31")
+	if node != nil {
+		t.Errorf("unexpected non-nil node: %#v", node)
+	}
+	expectedAPIErr := &APIError{
+		Status: http.StatusNotFound,
+		Errors: errorResponse,
+	}
+	apiErr := err.(*APIError)
+	if !reflect.DeepEqual(apiErr, expectedAPIErr) {
+		t.Errorf("wrong error returned\nwant %#v\ngot  %#v", expectedAPIErr, apiErr)
+	}
+}
+
+func TestGetNodeJobs(t *testing.T) {
+	server, requests := startServer(http.StatusOK, `<?xml version="1.0" encoding="UTF-8"?>
+<job_list>
+  <job href="/jobs/10">
+    <status>running</status>
+    <pct_complete>40</pct_complete>
+  </job>
+  <job href="/jobs/11">
+    <status>running</status>
+    <pct_complete>5</pct_complete>
+  </job>
+</job_list>`)
+	defer server.Close()
+	client := NewClient(server.URL, "myuser", "secret-key", 45, "aws-access-key", "aws-secret-key", "destination")
+	jobs, err := client.GetNodeJobs("31")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedJobs := &JobList{
+		XMLName: xml.Name{Local: "job_list"},
+		Job: []Job{
+			{XMLName: xml.Name{Local: "job"}, Href: "/jobs/10", Status: "running", PercentComplete: 40},
+			{XMLName: xml.Name{Local: "job"}, Href: "/jobs/11", Status: "running", PercentComplete: 5},
+		},
+	}
+	if !reflect.DeepEqual(jobs, expectedJobs) {
+		t.Errorf("wrong jobs returned\nwant %#v\ngot  %#v", expectedJobs, jobs)
+	}
+
+	fakeReq := <-requests
+	if expectedPath := "/api/nodes/31/jobs"; fakeReq.req.URL.Path != expectedPath {
+		t.Errorf("wrong path used\nwant %q\ngot  %q", expectedPath, fakeReq.req.URL.Path)
+	}
+}
+
+func TestNodeGetID(t *testing.T) {
+	var tests = []struct {
+		href string
+		id   string
+	}{
+		{"/nodes/31", "31"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.href, func(t *testing.T) {
+			n := Node{Href: test.href}
+			if id := n.GetID(); id != test.id {
+				t.Errorf("wrong id\nwant %q\ngot  %q", test.id, id)
+			}
+		})
+	}
+}
@@ -34,6 +34,18 @@ func (c *Client) CreatePreset(preset *Preset) (*Preset, error) {
 	return result, nil
 }
 
+// UpdatePreset replaces the preset with the given presetID, keeping its
+// identifier.
+func (c *Client) UpdatePreset(presetID string, preset *Preset) (*Preset, error) {
+	var result *Preset
+	err := c.do("PUT", "/presets/"+presetID, preset, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // DeletePreset removes a preset based on its presetID
 func (c *Client) DeletePreset(presetID string) error {
 	return c.do("DELETE", "/presets/"+presetID, nil, nil)
@@ -47,22 +59,23 @@ type PresetList struct {
 
 // Preset represents a preset
 type Preset struct {
-	XMLName       xml.Name `xml:"preset"`
-	Name          string   `xml:"name"`
-	Href          string   `xml:"href,attr,omitempty"`
-	Permalink     string   `xml:"permalink,omitempty"`
-	Description   string   `xml:"description,omitempty"`
-	Container     string   `xml:"container,omitempty"`
-	Width         string   `xml:"video_description>width,omitempty"`
-	Height        string   `xml:"video_description>height,omitempty"`
-	VideoCodec    string   `xml:"video_description>codec,omitempty"`
-	VideoBitrate  string   `xml:"video_description>h264_settings>bitrate,omitempty"`
-	GopSize       string   `xml:"video_description>h264_settings>gop_size,omitempty"`
-	GopMode       string   `xml:"video_description>h264_settings>gop_mode,omitempty"`
-	Profile       string   `xml:"video_description>h264_settings>profile,omitempty"`
-	ProfileLevel  string   `xml:"video_description>h264_settings>level,omitempty"`
-	RateControl   string   `xml:"video_description>h264_settings>rate_control_mode,omitempty"`
-	InterlaceMode string   `xml:"video_description>h264_settings>interlace_mode,omitempty"`
-	AudioCodec    string   `xml:"audio_description>codec,omitempty"`
-	AudioBitrate  string   `xml:"audio_description>aac_settings>bitrate,omitempty"`
+	XMLName       xml.Name      `xml:"preset"`
+	Name          string        `xml:"name"`
+	Href          string        `xml:"href,attr,omitempty"`
+	Permalink     string        `xml:"permalink,omitempty"`
+	Description   string        `xml:"description,omitempty"`
+	Container     string        `xml:"container,omitempty"`
+	Width         string        `xml:"video_description>width,omitempty"`
+	Height        string        `xml:"video_description>height,omitempty"`
+	VideoCodec    string        `xml:"video_description>codec,omitempty"`
+	VideoBitrate  string        `xml:"video_description>h264_settings>bitrate,omitempty"`
+	GopSize       string        `xml:"video_description>h264_settings>gop_size,omitempty"`
+	GopMode       string        `xml:"video_description>h264_settings>gop_mode,omitempty"`
+	Profile       string        `xml:"video_description>h264_settings>profile,omitempty"`
+	ProfileLevel  string        `xml:"video_description>h264_settings>level,omitempty"`
+	RateControl   string        `xml:"video_description>h264_settings>rate_control_mode,omitempty"`
+	InterlaceMode string        `xml:"video_description>h264_settings>interlace_mode,omitempty"`
+	H265Settings  *H265Settings `xml:"video_description>h265_settings,omitempty"`
+	AudioCodec    string        `xml:"audio_description>codec,omitempty"`
+	AudioBitrate  string        `xml:"audio_description>aac_settings>bitrate,omitempty"`
 }
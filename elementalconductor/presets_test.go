@@ -491,6 +491,53 @@ func TestCreatePreset(t *testing.T) {
 	}
 }
 
+func TestUpdatePreset(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<preset href="/presets/42">
+  <name>mp4_720p</name>
+  <container>mp4</container>
+  <video_description>
+    <height>720</height>
+    <codec>h.264</codec>
+  </video_description>
+</preset>`
+	server, requests := startServer(http.StatusOK, data)
+	defer server.Close()
+	client := NewClient(server.URL, "myuser", "secret-key", 45, "aws-access-key", "aws-secret-key", "destination")
+	preset := Preset{
+		XMLName:    xml.Name{Local: "preset"},
+		Name:       "mp4_720p",
+		Container:  "mp4",
+		Height:     "720",
+		VideoCodec: "h.264",
+	}
+	res, err := client.UpdatePreset("42", &preset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedPreset := preset
+	expectedPreset.Href = "/presets/42"
+	if !reflect.DeepEqual(*res, expectedPreset) {
+		t.Errorf("wrong preset returned\nwant %#v\ngot  %#v", expectedPreset, *res)
+	}
+
+	fakeReq := <-requests
+	if fakeReq.req.Method != http.MethodPut {
+		t.Errorf("wrong http method\nwant %q\ngot  %q", http.MethodPut, fakeReq.req.Method)
+	}
+	if expectedPath := "/api/presets/42"; fakeReq.req.URL.Path != expectedPath {
+		t.Errorf("wrong request path\nwant %q\ngot  %q", expectedPath, fakeReq.req.URL.Path)
+	}
+	var sentPreset Preset
+	err = xml.Unmarshal(fakeReq.body, &sentPreset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(sentPreset, preset) {
+		t.Errorf("wrong preset sent\nwant %#v\ngot  %#v", preset, sentPreset)
+	}
+}
+
 func TestDeletePreset(t *testing.T) {
 	presetsResponse := ` `
 	server, _ := startServer(http.StatusOK, presetsResponse)
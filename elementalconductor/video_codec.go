@@ -0,0 +1,142 @@
+package elementalconductor
+
+import "fmt"
+
+// VideoCodec is the codec used for encoding the video in a stream assembly.
+type VideoCodec string
+
+const (
+	// H264Codec is the codec for H.264/AVC video.
+	H264Codec = VideoCodec("h.264")
+	// H265Codec is the codec for H.265/HEVC video.
+	H265Codec = VideoCodec("h.265")
+)
+
+// H265Profile is the profile used for encoding H.265 video.
+type H265Profile string
+
+const (
+	// H265ProfileMain is the 8-bit main profile.
+	H265ProfileMain = H265Profile("Main")
+	// H265ProfileMain10 is the 10-bit main profile, required for HDR
+	// outputs.
+	H265ProfileMain10 = H265Profile("Main10")
+)
+
+// H265Tier is the tier used for encoding H.265 video.
+type H265Tier string
+
+const (
+	// H265TierMain is the main tier.
+	H265TierMain = H265Tier("Main")
+	// H265TierHigh is the high tier.
+	H265TierHigh = H265Tier("High")
+)
+
+// VideoCodecSettings is the interface implemented by codec-specific settings
+// in StreamVideoDescription, such as H264Settings and H265Settings. Only the
+// settings types defined in this package can be stored in a video
+// description, as each codec has its own field in the XML representation.
+type VideoCodecSettings interface {
+	// Codec returns the codec that the settings apply to.
+	Codec() VideoCodec
+}
+
+// H264Settings contains settings for encoding H.264 video.
+type H264Settings struct {
+	Bitrate         string `xml:"bitrate,omitempty"`
+	MaxBitrate      string `xml:"max_bitrate,omitempty"`
+	BufSize         string `xml:"buf_size,omitempty"`
+	GopSize         string `xml:"gop_size,omitempty"`
+	GopMode         string `xml:"gop_mode,omitempty"`
+	Profile         string `xml:"profile,omitempty"`
+	Level           string `xml:"level,omitempty"`
+	RateControlMode string `xml:"rate_control_mode,omitempty"`
+	InterlaceMode   string `xml:"interlace_mode,omitempty"`
+}
+
+// Codec returns H264Codec.
+func (*H264Settings) Codec() VideoCodec {
+	return H264Codec
+}
+
+// H265Settings contains settings for encoding H.265 video. HDR10Metadata
+// should only be used along with H265ProfileMain10.
+type H265Settings struct {
+	Bitrate         string         `xml:"bitrate,omitempty"`
+	MaxBitrate      string         `xml:"max_bitrate,omitempty"`
+	BufSize         string         `xml:"buf_size,omitempty"`
+	GopSize         string         `xml:"gop_size,omitempty"`
+	GopMode         string         `xml:"gop_mode,omitempty"`
+	Profile         H265Profile    `xml:"profile,omitempty"`
+	Tier            H265Tier       `xml:"tier,omitempty"`
+	Level           string         `xml:"level,omitempty"`
+	RateControlMode string         `xml:"rate_control_mode,omitempty"`
+	InterlaceMode   string         `xml:"interlace_mode,omitempty"`
+	HDR10Metadata   *HDR10Metadata `xml:"hdr10_metadata,omitempty"`
+}
+
+// Codec returns H265Codec.
+func (*H265Settings) Codec() VideoCodec {
+	return H265Codec
+}
+
+// HDR10Metadata contains the static HDR10 metadata (SMPTE ST 2086 mastering
+// display information and content light levels) to be inserted in the
+// output.
+//
+// Chromaticity coordinates are expressed in units of 0.00002, luminance
+// values are expressed in units of 0.0001 cd/m², and MaxCLL and MaxFALL are
+// expressed in cd/m².
+type HDR10Metadata struct {
+	RedPrimaryX   int `xml:"red_primary_x,omitempty"`
+	RedPrimaryY   int `xml:"red_primary_y,omitempty"`
+	GreenPrimaryX int `xml:"green_primary_x,omitempty"`
+	GreenPrimaryY int `xml:"green_primary_y,omitempty"`
+	BluePrimaryX  int `xml:"blue_primary_x,omitempty"`
+	BluePrimaryY  int `xml:"blue_primary_y,omitempty"`
+	WhitePointX   int `xml:"white_point_x,omitempty"`
+	WhitePointY   int `xml:"white_point_y,omitempty"`
+	MaxLuminance  int `xml:"max_luminance,omitempty"`
+	MinLuminance  int `xml:"min_luminance,omitempty"`
+	MaxCLL        int `xml:"max_cll,omitempty"`
+	MaxFALL       int `xml:"max_fall,omitempty"`
+}
+
+// CodecSettings returns the codec-specific settings defined in the video
+// description, or nil if there are no codec-specific settings.
+func (s *StreamVideoDescription) CodecSettings() VideoCodecSettings {
+	switch {
+	case s.H264Settings != nil:
+		return s.H264Settings
+	case s.H265Settings != nil:
+		return s.H265Settings
+	}
+	return nil
+}
+
+// SetCodecSettings replaces the codec-specific settings in the video
+// description with the given settings, and updates Codec accordingly. Passing
+// nil removes the codec-specific settings, keeping Codec unchanged.
+//
+// It returns an error, leaving the video description untouched, when the
+// settings type isn't supported.
+func (s *StreamVideoDescription) SetCodecSettings(settings VideoCodecSettings) error {
+	var h264 *H264Settings
+	var h265 *H265Settings
+	switch v := settings.(type) {
+	case nil:
+	case *H264Settings:
+		h264 = v
+	case *H265Settings:
+		h265 = v
+	default:
+		return fmt.Errorf("unsupported video codec settings type %T", settings)
+	}
+	s.H264Settings = h264
+	s.H265Settings = h265
+	if settings != nil {
+		s.Codec = string(settings.Codec())
+	}
+	return nil
+}
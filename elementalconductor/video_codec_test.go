@@ -0,0 +1,168 @@
+package elementalconductor
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStreamVideoDescriptionH265(t *testing.T) {
+	data := `<video_description>
+    <codec>h.265</codec>
+    <encoder_type>cpu</encoder_type>
+    <height>2160</height>
+    <width>3840</width>
+    <h265_settings>
+        <bitrate>15000000</bitrate>
+        <gop_size>2</gop_size>
+        <gop_mode>seconds</gop_mode>
+        <profile>Main10</profile>
+        <tier>High</tier>
+        <level>5.1</level>
+        <rate_control_mode>VBR</rate_control_mode>
+        <hdr10_metadata>
+            <red_primary_x>35400</red_primary_x>
+            <red_primary_y>14600</red_primary_y>
+            <green_primary_x>8500</green_primary_x>
+            <green_primary_y>39850</green_primary_y>
+            <blue_primary_x>6550</blue_primary_x>
+            <blue_primary_y>2300</blue_primary_y>
+            <white_point_x>15635</white_point_x>
+            <white_point_y>16450</white_point_y>
+            <max_luminance>10000000</max_luminance>
+            <min_luminance>50</min_luminance>
+            <max_cll>1000</max_cll>
+            <max_fall>400</max_fall>
+        </hdr10_metadata>
+    </h265_settings>
+</video_description>`
+	expected := StreamVideoDescription{
+		Codec:       "h.265",
+		EncoderType: "cpu",
+		Height:      "2160",
+		Width:       "3840",
+		H265Settings: &H265Settings{
+			Bitrate:         "15000000",
+			GopSize:         "2",
+			GopMode:         "seconds",
+			Profile:         H265ProfileMain10,
+			Tier:            H265TierHigh,
+			Level:           "5.1",
+			RateControlMode: "VBR",
+			HDR10Metadata: &HDR10Metadata{
+				RedPrimaryX:   35400,
+				RedPrimaryY:   14600,
+				GreenPrimaryX: 8500,
+				GreenPrimaryY: 39850,
+				BluePrimaryX:  6550,
+				BluePrimaryY:  2300,
+				WhitePointX:   15635,
+				WhitePointY:   16450,
+				MaxLuminance:  10000000,
+				MinLuminance:  50,
+				MaxCLL:        1000,
+				MaxFALL:       400,
+			},
+		},
+	}
+	var got StreamVideoDescription
+	err := xml.Unmarshal([]byte(data), &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong video description\nwant %#v\ngot  %#v", expected, got)
+	}
+	if settings := got.CodecSettings(); settings != got.H265Settings {
+		t.Errorf("wrong codec settings\nwant %#v\ngot  %#v", got.H265Settings, settings)
+	}
+}
+
+func TestStreamVideoDescriptionSetCodecSettings(t *testing.T) {
+	var tests = []struct {
+		name     string
+		settings VideoCodecSettings
+		codec    string
+	}{
+		{"h264", &H264Settings{Bitrate: "5000000"}, "h.264"},
+		{"h265", &H265Settings{Bitrate: "5000000"}, "h.265"},
+		{"nil", nil, "h.264"},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			desc := StreamVideoDescription{
+				Codec:        "h.264",
+				H264Settings: &H264Settings{Bitrate: "1000000"},
+			}
+			if err := desc.SetCodecSettings(test.settings); err != nil {
+				t.Fatal(err)
+			}
+			if desc.Codec != test.codec {
+				t.Errorf("wrong codec\nwant %q\ngot  %q", test.codec, desc.Codec)
+			}
+			if got := desc.CodecSettings(); !reflect.DeepEqual(got, test.settings) {
+				t.Errorf("wrong codec settings\nwant %#v\ngot  %#v", test.settings, got)
+			}
+		})
+	}
+}
+
+type vp9Settings struct{}
+
+func (vp9Settings) Codec() VideoCodec {
+	return VideoCodec("vp9")
+}
+
+func TestStreamVideoDescriptionSetCodecSettingsUnsupported(t *testing.T) {
+	desc := StreamVideoDescription{
+		Codec:        "h.264",
+		H264Settings: &H264Settings{Bitrate: "1000000"},
+	}
+	expected := desc
+	err := desc.SetCodecSettings(vp9Settings{})
+	expectedMsg := "unsupported video codec settings type elementalconductor.vp9Settings"
+	if err == nil || err.Error() != expectedMsg {
+		t.Errorf("wrong error\nwant %q\ngot  %v", expectedMsg, err)
+	}
+	if !reflect.DeepEqual(desc, expected) {
+		t.Errorf("video description was modified\nwant %#v\ngot  %#v", expected, desc)
+	}
+}
+
+func TestPresetH265Marshaling(t *testing.T) {
+	preset := Preset{
+		Name:       "hevc_hdr10",
+		Container:  "mp4",
+		Width:      "3840",
+		Height:     "2160",
+		VideoCodec: "h.265",
+		H265Settings: &H265Settings{
+			Bitrate: "15000000",
+			Profile: H265ProfileMain10,
+			Tier:    H265TierMain,
+			HDR10Metadata: &HDR10Metadata{
+				MaxCLL:  1000,
+				MaxFALL: 400,
+			},
+		},
+		AudioCodec: "aac",
+	}
+	data, err := xml.Marshal(preset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "<video_description>"); n != 1 {
+		t.Errorf("wrong number of video_description elements\nwant 1\ngot  %d\n%s", n, data)
+	}
+	var got Preset
+	err = xml.Unmarshal(data, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	preset.XMLName = xml.Name{Local: "preset"}
+	if !reflect.DeepEqual(got, preset) {
+		t.Errorf("wrong preset after marshaling\nwant %#v\ngot  %#v", preset, got)
+	}
+}
@@ -0,0 +1,147 @@
+package elementalconductor
+
+// DeinterlaceMode is the mode used by the Deinterlacer preprocessor.
+type DeinterlaceMode string
+
+const (
+	// DeinterlaceModeDeinterlace always deinterlaces the input.
+	DeinterlaceModeDeinterlace = DeinterlaceMode("Deinterlace")
+	// DeinterlaceModeInverseTelecine removes 3:2 pulldown from the input.
+	DeinterlaceModeInverseTelecine = DeinterlaceMode("Inverse Telecine")
+	// DeinterlaceModeAdaptive detects whether the input is interlaced or
+	// telecined and picks the proper processing.
+	DeinterlaceModeAdaptive = DeinterlaceMode("Adaptive")
+)
+
+// DeinterlaceAlgorithm is the algorithm used by the Deinterlacer
+// preprocessor.
+type DeinterlaceAlgorithm string
+
+const (
+	// DeinterlaceAlgorithmMotionAdaptiveInterpolation is the motion
+	// adaptive interpolation algorithm.
+	DeinterlaceAlgorithmMotionAdaptiveInterpolation = DeinterlaceAlgorithm("Motion Adaptive Interpolation")
+	// DeinterlaceAlgorithmMotionAdaptiveBlend is the motion adaptive blend
+	// algorithm.
+	DeinterlaceAlgorithmMotionAdaptiveBlend = DeinterlaceAlgorithm("Motion Adaptive Blend")
+	// DeinterlaceAlgorithmLowLatencyInterpolation is the low latency
+	// interpolation algorithm.
+	DeinterlaceAlgorithmLowLatencyInterpolation = DeinterlaceAlgorithm("Low Latency Interpolation")
+)
+
+// ColorSpaceConversion is the conversion applied by the ColorCorrector
+// preprocessor.
+type ColorSpaceConversion string
+
+const (
+	// ColorSpaceConversionNone keeps the color space of the input.
+	ColorSpaceConversionNone = ColorSpaceConversion("None")
+	// ColorSpaceConversionForce601 converts the video to the Rec. 601 color
+	// space.
+	ColorSpaceConversionForce601 = ColorSpaceConversion("Force 601")
+	// ColorSpaceConversionForce709 converts the video to the Rec. 709 color
+	// space.
+	ColorSpaceConversionForce709 = ColorSpaceConversion("Force 709")
+	// ColorSpaceConversionForceHDR10 converts the video to the HDR10 color
+	// space.
+	ColorSpaceConversionForceHDR10 = ColorSpaceConversion("Force HDR10")
+)
+
+// NoiseReducerFilter is the filter used by the NoiseReducer preprocessor.
+type NoiseReducerFilter string
+
+const (
+	// NoiseReducerFilterBilateral is the bilateral filter.
+	NoiseReducerFilterBilateral = NoiseReducerFilter("Bilateral")
+	// NoiseReducerFilterMean is the mean filter.
+	NoiseReducerFilterMean = NoiseReducerFilter("Mean")
+	// NoiseReducerFilterGaussian is the gaussian filter.
+	NoiseReducerFilterGaussian = NoiseReducerFilter("Gaussian")
+	// NoiseReducerFilterLanczos is the lanczos filter.
+	NoiseReducerFilterLanczos = NoiseReducerFilter("Lanczos")
+	// NoiseReducerFilterSharpen is the sharpen filter.
+	NoiseReducerFilterSharpen = NoiseReducerFilter("Sharpen")
+	// NoiseReducerFilterConserve is the conserve filter.
+	NoiseReducerFilterConserve = NoiseReducerFilter("Conserve")
+	// NoiseReducerFilterSpatial is the spatial filter.
+	NoiseReducerFilterSpatial = NoiseReducerFilter("Spatial")
+	// NoiseReducerFilterTemporal is the temporal filter.
+	NoiseReducerFilterTemporal = NoiseReducerFilter("Temporal")
+)
+
+// VideoPreprocessors contains the processing that should be applied to the
+// video before encoding it in a stream assembly.
+type VideoPreprocessors struct {
+	ColorCorrector *ColorCorrector      `xml:"color_corrector,omitempty"`
+	Deinterlacer   *Deinterlacer        `xml:"deinterlacer,omitempty"`
+	FrameRate      *FrameRateConversion `xml:"frame_rate,omitempty"`
+	ImageInserter  *ImageInserter       `xml:"image_inserter,omitempty"`
+	NoiseReducer   *NoiseReducer        `xml:"noise_reducer,omitempty"`
+}
+
+// ColorCorrector contains settings for adjusting colors and converting the
+// color space of the video. Brightness, Contrast and Saturation range from 1
+// to 100, and Hue ranges from -180 to 180.
+type ColorCorrector struct {
+	Brightness           int                  `xml:"brightness,omitempty"`
+	Contrast             int                  `xml:"contrast,omitempty"`
+	Hue                  int                  `xml:"hue,omitempty"`
+	Saturation           int                  `xml:"saturation,omitempty"`
+	ColorSpaceConversion ColorSpaceConversion `xml:"color_space_conversion,omitempty"`
+}
+
+// Deinterlacer contains settings for deinterlacing the video. When Force is
+// true, the video is processed even if it's flagged as progressive.
+type Deinterlacer struct {
+	Algorithm       DeinterlaceAlgorithm `xml:"algorithm,omitempty"`
+	DeinterlaceMode DeinterlaceMode      `xml:"deinterlace_mode,omitempty"`
+	Force           bool                 `xml:"force,omitempty"`
+}
+
+// FrameRateConversion contains settings for converting the frame rate of
+// the video to FramerateNumerator/FramerateDenominator. When Interpolated
+// is true, new frames are generated by interpolation instead of being
+// dropped or duplicated.
+type FrameRateConversion struct {
+	FramerateNumerator   int  `xml:"framerate_numerator,omitempty"`
+	FramerateDenominator int  `xml:"framerate_denominator,omitempty"`
+	Interpolated         bool `xml:"interpolated,omitempty"`
+}
+
+// NoiseReducer contains settings for reducing noise in the video. Strength
+// ranges from 0 to 3, and is omitted when nil (using the default of the
+// Conductor).
+type NoiseReducer struct {
+	Filter   NoiseReducerFilter `xml:"filter,omitempty"`
+	Strength *int               `xml:"strength,omitempty"`
+}
+
+// ImageInserter contains the list of images (watermarks or graphic overlays)
+// that should be inserted on top of the video.
+type ImageInserter struct {
+	InsertableImages []InsertableImage `xml:"insertable_images>insertable_image,omitempty"`
+}
+
+// InsertableImage defines a single image to be inserted on top of the
+// video.
+//
+// ImageX and ImageY define the position of the top-left corner of the
+// image, in pixels. Opacity is a value between 0 and 100, and is omitted when
+// nil (using the default of the Conductor), so 0 can be used for a fully
+// transparent image. StartTime is the
+// timecode (HH:MM:SS:FF) where the image first shows up, and Duration, FadeIn
+// and FadeOut are expressed in milliseconds. When Duration is zero, the image
+// stays on until the end of the video.
+type InsertableImage struct {
+	ImageInserterInput *Location `xml:"image_inserter_input,omitempty"`
+	ImageX             int       `xml:"image_x,omitempty"`
+	ImageY             int       `xml:"image_y,omitempty"`
+	Width              int       `xml:"width,omitempty"`
+	Height             int       `xml:"height,omitempty"`
+	Layer              int       `xml:"layer,omitempty"`
+	Opacity            *int      `xml:"opacity,omitempty"`
+	StartTime          string    `xml:"start_time,omitempty"`
+	Duration           int       `xml:"duration,omitempty"`
+	FadeIn             int       `xml:"fade_in,omitempty"`
+	FadeOut            int       `xml:"fade_out,omitempty"`
+}
@@ -0,0 +1,212 @@
+package elementalconductor
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStreamAssemblyImageInserter(t *testing.T) {
+	data := `<stream_assembly>
+    <name>stream_1</name>
+    <preset>17</preset>
+    <video_description>
+        <codec>h.264</codec>
+        <encoder_type>gpu</encoder_type>
+        <height>1080</height>
+        <width>1920</width>
+        <video_preprocessors>
+            <image_inserter>
+                <insertable_images>
+                    <insertable_image>
+                        <image_inserter_input>
+                            <uri>s3://mybucket/logo.png</uri>
+                        </image_inserter_input>
+                        <image_x>1700</image_x>
+                        <image_y>40</image_y>
+                        <layer>1</layer>
+                        <opacity>50</opacity>
+                    </insertable_image>
+                    <insertable_image>
+                        <image_inserter_input>
+                            <uri>s3://mybucket/lower-third.png</uri>
+                        </image_inserter_input>
+                        <image_y>900</image_y>
+                        <layer>2</layer>
+                        <opacity>0</opacity>
+                        <start_time>00:00:05:00</start_time>
+                        <duration>10000</duration>
+                        <fade_in>500</fade_in>
+                        <fade_out>500</fade_out>
+                    </insertable_image>
+                </insertable_images>
+            </image_inserter>
+        </video_preprocessors>
+    </video_description>
+</stream_assembly>`
+	logoOpacity, lowerThirdOpacity := 50, 0
+	expected := StreamAssembly{
+		Name:   "stream_1",
+		Preset: "17",
+		VideoDescription: &StreamVideoDescription{
+			Codec:       "h.264",
+			EncoderType: "gpu",
+			Height:      "1080",
+			Width:       "1920",
+			VideoPreprocessors: &VideoPreprocessors{
+				ImageInserter: &ImageInserter{
+					InsertableImages: []InsertableImage{
+						{
+							ImageInserterInput: &Location{URI: "s3://mybucket/logo.png"},
+							ImageX:             1700,
+							ImageY:             40,
+							Layer:              1,
+							Opacity:            &logoOpacity,
+						},
+						{
+							ImageInserterInput: &Location{URI: "s3://mybucket/lower-third.png"},
+							ImageY:             900,
+							Layer:              2,
+							Opacity:            &lowerThirdOpacity,
+							StartTime:          "00:00:05:00",
+							Duration:           10000,
+							FadeIn:             500,
+							FadeOut:            500,
+						},
+					},
+				},
+			},
+		},
+	}
+	var got StreamAssembly
+	err := xml.Unmarshal([]byte(data), &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong stream assembly\nwant %#v\ngot  %#v", expected, got)
+	}
+
+	marshaled, err := xml.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(marshaled), "<opacity>0</opacity>") {
+		t.Errorf("zero opacity not included in the XML: %s", marshaled)
+	}
+	var roundTrip StreamAssembly
+	err = xml.Unmarshal(marshaled, &roundTrip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(roundTrip, expected) {
+		t.Errorf("wrong stream assembly after marshaling\nwant %#v\ngot  %#v", expected, roundTrip)
+	}
+}
+
+func TestStreamAssemblyVideoPreprocessors(t *testing.T) {
+	data := `<stream_assembly>
+    <name>stream_1</name>
+    <video_description>
+        <anti_alias>true</anti_alias>
+        <codec>h.264</codec>
+        <encoder_type>cpu</encoder_type>
+        <height>720</height>
+        <stretch_to_output>true</stretch_to_output>
+        <width>1280</width>
+        <video_preprocessors>
+            <color_corrector>
+                <brightness>50</brightness>
+                <contrast>55</contrast>
+                <hue>-10</hue>
+                <saturation>45</saturation>
+                <color_space_conversion>Force 709</color_space_conversion>
+            </color_corrector>
+            <deinterlacer>
+                <algorithm>Motion Adaptive Interpolation</algorithm>
+                <deinterlace_mode>Inverse Telecine</deinterlace_mode>
+                <force>true</force>
+            </deinterlacer>
+            <frame_rate>
+                <framerate_numerator>30000</framerate_numerator>
+                <framerate_denominator>1001</framerate_denominator>
+                <interpolated>true</interpolated>
+            </frame_rate>
+            <noise_reducer>
+                <filter>Temporal</filter>
+                <strength>2</strength>
+            </noise_reducer>
+        </video_preprocessors>
+    </video_description>
+</stream_assembly>`
+	antiAlias, stretchToOutput, strength := true, true, 2
+	expected := StreamAssembly{
+		Name: "stream_1",
+		VideoDescription: &StreamVideoDescription{
+			AntiAlias:       &antiAlias,
+			Codec:           "h.264",
+			EncoderType:     "cpu",
+			Height:          "720",
+			StretchToOutput: &stretchToOutput,
+			Width:           "1280",
+			VideoPreprocessors: &VideoPreprocessors{
+				ColorCorrector: &ColorCorrector{
+					Brightness:           50,
+					Contrast:             55,
+					Hue:                  -10,
+					Saturation:           45,
+					ColorSpaceConversion: ColorSpaceConversionForce709,
+				},
+				Deinterlacer: &Deinterlacer{
+					Algorithm:       DeinterlaceAlgorithmMotionAdaptiveInterpolation,
+					DeinterlaceMode: DeinterlaceModeInverseTelecine,
+					Force:           true,
+				},
+				FrameRate: &FrameRateConversion{
+					FramerateNumerator:   30000,
+					FramerateDenominator: 1001,
+					Interpolated:         true,
+				},
+				NoiseReducer: &NoiseReducer{
+					Filter:   NoiseReducerFilterTemporal,
+					Strength: &strength,
+				},
+			},
+		},
+	}
+	var got StreamAssembly
+	err := xml.Unmarshal([]byte(data), &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong stream assembly\nwant %#v\ngot  %#v", expected, got)
+	}
+
+	marshaled, err := xml.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var roundTrip StreamAssembly
+	err = xml.Unmarshal(marshaled, &roundTrip)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(roundTrip, expected) {
+		t.Errorf("wrong stream assembly after marshaling\nwant %#v\ngot  %#v", expected, roundTrip)
+	}
+}
+
+func TestNoiseReducerZeroStrength(t *testing.T) {
+	strength := 0
+	reducer := NoiseReducer{Filter: NoiseReducerFilterTemporal, Strength: &strength}
+	data, err := xml.Marshal(reducer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "<NoiseReducer><filter>Temporal</filter><strength>0</strength></NoiseReducer>"
+	if string(data) != expected {
+		t.Errorf("wrong XML\nwant %s\ngot  %s", expected, data)
+	}
+}
@@ -6,24 +6,99 @@ package encodingcom // import "github.com/NYTimes/encoding-wrapper/encodingcom"
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"reflect"
 	"strings"
+	"time"
 )
 
+// DefaultTimeZone is the timezone used by the Encoding.com API when returning
+// dates.
+const DefaultTimeZone = "America/New_York"
+
+// DefaultLocation is the location used for interpreting dates returned by
+// the Encoding.com API in clients that don't define a Location. It's loaded
+// from DefaultTimeZone, falling back to the current US Eastern rules (UTC-5,
+// with daylight saving time from the second Sunday of March to the first
+// Sunday of November) when timezone information isn't available in the
+// system. The fallback doesn't know about historical rule changes, so dates
+// before 2007 may be off by one hour.
+var DefaultLocation = loadDefaultLocation()
+
+// easternTZData is a minimal TZif file with no transitions, describing
+// DefaultTimeZone using only the POSIX TZ rule in its footer.
+const easternTZData = "TZif2\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x01\x00\x00\x00\x04\xff\xff\xb9\xb0\x00\x00EST\x00TZif2\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x01\x00\x00\x00\x04\xff\xff\xb9\xb0\x00\x00EST\x00\x0aEST5EDT,M3.2.0,M11.1.0\x0a"
+
+func loadDefaultLocation() *time.Location {
+	loc, err := time.LoadLocation(DefaultTimeZone)
+	if err != nil {
+		return fallbackLocation()
+	}
+	return loc
+}
+
+func fallbackLocation() *time.Location {
+	loc, err := time.LoadLocationFromTZData(DefaultTimeZone, []byte(easternTZData))
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
 // Client is the basic type for interacting with the API. It provides methods
 // matching the available actions in the API.
+//
+// Location is the timezone of the dates returned by the API, DefaultLocation
+// is used when it's nil (see DefaultLocation for the rules used when the
+// system has no timezone information). WireFormat is the format used for communicating with
+// the API, JSONWireFormat is used when it's empty. HTTPClient is the client
+// used for sending requests to the API, http.DefaultClient is used when it's
+// nil. ValidateFormats enables the validation of formats in AddMedia.
 type Client struct {
-	Endpoint string
-	UserID   string
-	UserKey  string
+	Endpoint        string
+	UserID          string
+	UserKey         string
+	Location        *time.Location
+	WireFormat      WireFormat
+	HTTPClient      *http.Client
+	ValidateFormats bool
 }
 
+// WireFormat is the format used by the client when sending requests to and
+// reading responses from the Encoding.com API.
+type WireFormat string
+
+const (
+	// JSONWireFormat sends requests in the "json" form field and reads JSON
+	// responses.
+	JSONWireFormat = WireFormat("json")
+
+	// XMLWireFormat sends requests in the "xml" form field and reads XML
+	// responses.
+	XMLWireFormat = WireFormat("xml")
+)
+
 // NewClient creates a instance of the client type.
 func NewClient(endpoint, userID, userKey string) (*Client, error) {
-	return &Client{Endpoint: endpoint, UserID: userID, UserKey: userKey}, nil
+	return &Client{Endpoint: endpoint, UserID: userID, UserKey: userKey, Location: DefaultLocation}, nil
+}
+
+func (c *Client) location() *time.Location {
+	if c.Location != nil {
+		return c.Location
+	}
+	return DefaultLocation
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient != nil {
+		return c.HTTPClient
+	}
+	return http.DefaultClient
 }
 
 // Response represents the generic response in the Encoding.com API. It doesn't
@@ -32,7 +107,7 @@ func NewClient(endpoint, userID, userKey string) (*Client, error) {
 //
 // See http://goo.gl/GBEn98 for more details.
 type Response struct {
-	Message string `json:"message,omitempty"`
+	Message string `json:"message,omitempty" xml:"message,omitempty"`
 }
 
 func (c *Client) doMediaAction(mediaID string, action string) (*Response, error) {
@@ -50,6 +125,9 @@ func (c *Client) doMediaAction(mediaID string, action string) (*Response, error)
 func (c *Client) do(r *request, out interface{}) error {
 	r.UserID = c.UserID
 	r.UserKey = c.UserKey
+	if c.WireFormat == XMLWireFormat {
+		return c.doXML(r, out)
+	}
 	jsonRequest, err := json.Marshal(r)
 	if err != nil {
 		return err
@@ -60,34 +138,93 @@ func (c *Client) do(r *request, out interface{}) error {
 	if err != nil {
 		return err
 	}
-	params := url.Values{}
-	params.Add("json", string(reqData))
-	req, err := http.NewRequest("POST", c.Endpoint, strings.NewReader(params.Encode()))
+	respData, err := c.post("json", reqData)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := http.DefaultClient.Do(req)
+	var errRespWrapper map[string]*errorResponse
+	err = json.Unmarshal(respData, &errRespWrapper)
+	if err != nil {
+		return fmt.Errorf("Error unmarshaling response: %s", err.Error())
+	}
+	if errResp := errRespWrapper["response"]; errResp.Errors.Error != "" {
+		return &APIError{
+			Message: errResp.Message,
+			Errors:  []string{errResp.Errors.Error},
+		}
+	}
+	return json.Unmarshal(respData, out)
+}
+
+func (c *Client) doXML(r *request, out interface{}) error {
+	reqData, err := xml.Marshal(r)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-	respData, err := ioutil.ReadAll(resp.Body)
+	respData, err := c.post("xml", append([]byte(xml.Header), reqData...))
 	if err != nil {
 		return err
 	}
-	var errRespWrapper map[string]*errorResponse
-	err = json.Unmarshal(respData, &errRespWrapper)
+	var errResp errorResponse
+	err = xml.Unmarshal(respData, &errResp)
 	if err != nil {
 		return fmt.Errorf("Error unmarshaling response: %s", err.Error())
 	}
-	if errResp := errRespWrapper["response"]; errResp.Errors.Error != "" {
+	if errResp.Errors.Error != "" {
 		return &APIError{
 			Message: errResp.Message,
 			Errors:  []string{errResp.Errors.Error},
 		}
 	}
-	return json.Unmarshal(respData, out)
+	return unmarshalXMLResponse(respData, out)
+}
+
+// unmarshalXMLResponse unmarshals the given XML response into out. As
+// methods in the client expect responses wrapped in a map (matching the JSON
+// format, where the response is under the "response" key), when out is a
+// pointer to a map, the response is unmarshaled into a new value of the map
+// element type, stored in the "response" key.
+func unmarshalXMLResponse(data []byte, out interface{}) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Map {
+		return xml.Unmarshal(data, out)
+	}
+	m := v.Elem()
+	elemType := m.Type().Elem()
+	var elem reflect.Value
+	if elemType.Kind() == reflect.Ptr {
+		elem = reflect.New(elemType.Elem())
+	} else {
+		elem = reflect.New(elemType)
+	}
+	err := xml.Unmarshal(data, elem.Interface())
+	if err != nil {
+		return err
+	}
+	if elemType.Kind() != reflect.Ptr {
+		elem = elem.Elem()
+	}
+	if m.IsNil() {
+		m.Set(reflect.MakeMap(m.Type()))
+	}
+	m.SetMapIndex(reflect.ValueOf("response"), elem)
+	return nil
+}
+
+func (c *Client) post(field string, data []byte) ([]byte, error) {
+	params := url.Values{}
+	params.Add(field, string(data))
+	req, err := http.NewRequest("POST", c.Endpoint, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := c.httpClient().Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return ioutil.ReadAll(resp.Body)
 }
 
 // APIError represents an error returned by the Encoding.com API.
@@ -107,31 +244,32 @@ func (apiErr *APIError) Error() string {
 }
 
 type errorResponse struct {
-	Message string     `json:"message,omitempty"`
-	Errors  errorsJSON `json:"errors,omitempty"`
+	Message string     `json:"message,omitempty" xml:"message,omitempty"`
+	Errors  errorsJSON `json:"errors,omitempty" xml:"errors,omitempty"`
 }
 
 type errorsJSON struct {
-	Error string `json:"error,omitempty"`
+	Error string `json:"error,omitempty" xml:"error,omitempty"`
 }
 
 type request struct {
-	UserID                  string       `json:"userid"`
-	UserKey                 string       `json:"userkey"`
-	Action                  string       `json:"action"`
-	MediaID                 string       `json:"mediaid,omitempty"`
-	TaskID                  string       `json:"taskid,omitempty"`
-	Source                  []string     `json:"source,omitempty"`
-	SplitScreen             *SplitScreen `json:"split_screen,omitempty"`
-	Region                  string       `json:"region,omitempty"`
-	NotifyFormat            string       `json:"notify_format,omitempty"`
-	NotifyURL               string       `json:"notify,omitempty"`
-	NotifyEncodingErrorsURL string       `json:"notify_encoding_errors,omitempty"`
-	NotifyUploadURL         string       `json:"notify_upload,omitempty"`
-	Extended                YesNoBoolean `json:"extended,omitempty"`
-	Type                    string       `json:"type,omitempty"`
-	Name                    string       `json:"name,omitempty"`
-	Format                  []Format     `json:"format,omitempty"`
+	XMLName                 xml.Name     `json:"-" xml:"query"`
+	UserID                  string       `json:"userid" xml:"userid"`
+	UserKey                 string       `json:"userkey" xml:"userkey"`
+	Action                  string       `json:"action" xml:"action"`
+	MediaID                 string       `json:"mediaid,omitempty" xml:"mediaid,omitempty"`
+	TaskID                  string       `json:"taskid,omitempty" xml:"taskid,omitempty"`
+	Source                  []string     `json:"source,omitempty" xml:"source,omitempty"`
+	SplitScreen             *SplitScreen `json:"split_screen,omitempty" xml:"split_screen,omitempty"`
+	Region                  string       `json:"region,omitempty" xml:"region,omitempty"`
+	NotifyFormat            string       `json:"notify_format,omitempty" xml:"notify_format,omitempty"`
+	NotifyURL               string       `json:"notify,omitempty" xml:"notify,omitempty"`
+	NotifyEncodingErrorsURL string       `json:"notify_encoding_errors,omitempty" xml:"notify_encoding_errors,omitempty"`
+	NotifyUploadURL         string       `json:"notify_upload,omitempty" xml:"notify_upload,omitempty"`
+	Extended                YesNoBoolean `json:"extended,omitempty" xml:"extended,omitempty"`
+	Type                    string       `json:"type,omitempty" xml:"type,omitempty"`
+	Name                    string       `json:"name,omitempty" xml:"name,omitempty"`
+	Format                  []Format     `json:"format,omitempty" xml:"format,omitempty"`
 }
 
 // SplitScreen is the set of options for combining several sources to one split
@@ -139,12 +277,12 @@ type request struct {
 //
 // See http://goo.gl/EolKyv for more details.
 type SplitScreen struct {
-	Columns       int `json:"columns,string,omitempty"`
-	Rows          int `json:"rows,string,omitempty"`
-	PaddingLeft   int `json:"padding_left,string,omitempty"`
-	PaddingRight  int `json:"padding_right,string,omitempty"`
-	PaddingBottom int `json:"padding_bottom,string,omitempty"`
-	PaddingTop    int `json:"padding_top,string,omitempty"`
+	Columns       int `json:"columns,string,omitempty" xml:"columns,omitempty"`
+	Rows          int `json:"rows,string,omitempty" xml:"rows,omitempty"`
+	PaddingLeft   int `json:"padding_left,string,omitempty" xml:"padding_left,omitempty"`
+	PaddingRight  int `json:"padding_right,string,omitempty" xml:"padding_right,omitempty"`
+	PaddingBottom int `json:"padding_bottom,string,omitempty" xml:"padding_bottom,omitempty"`
+	PaddingTop    int `json:"padding_top,string,omitempty" xml:"padding_top,omitempty"`
 }
 
 // Format is the set of options for defining the output format when encoding
@@ -152,58 +290,58 @@ type SplitScreen struct {
 //
 // See http://goo.gl/dcE1pF for more details.
 type Format struct {
-	Output                  []string             `json:"output,omitempty"`
-	NoiseReduction          string               `json:"noise_reduction,omitempty"`
-	OutputPreset            string               `json:"output_preset,omitempty"`
-	VideoCodec              string               `json:"video_codec,omitempty"`
-	AudioCodec              string               `json:"audio_codec,omitempty"`
-	Bitrate                 string               `json:"bitrate,omitempty"`
-	AudioBitrate            string               `json:"audio_bitrate,omitempty"`
-	AudioChannelsNumber     string               `json:"audio_channels_number,omitempty"`
-	Framerate               string               `json:"framerate,omitempty"`
-	FramerateUpperThreshold string               `json:"framerate_upper_threshold,omitempty"`
-	Size                    string               `json:"size,omitempty"`
-	FadeIn                  string               `json:"fade_in,omitempty"`
-	FadeOut                 string               `json:"fade_out,omitempty"`
-	AudioSampleRate         uint                 `json:"audio_sample_rate,string,omitempty"`
-	AudioVolume             uint                 `json:"audio_volume,string,omitempty"`
-	CropLeft                int                  `json:"crop_left,string,omitempty"`
-	CropTop                 int                  `json:"crop_top,string,omitempty"`
-	CropRight               int                  `json:"crop_right,string,omitempty"`
-	CropBottom              int                  `json:"crop_bottom,string,omitempty"`
-	SetAspectRatio          string               `json:"set_aspect_ratio,omitempty"`
-	RcInitOccupancy         string               `json:"rc_init_occupancy,omitempty"`
-	MinRate                 string               `json:"minrate,omitempty"`
-	MaxRate                 string               `json:"maxrate,omitempty"`
-	BufSize                 string               `json:"bufsize,omitempty"`
-	Keyframe                []string             `json:"keyframe,omitempty"`
-	Start                   string               `json:"start,omitempty"`
-	Duration                string               `json:"duration,omitempty"`
-	ForceKeyframes          string               `json:"force_keyframes,omitempty"`
-	Bframes                 int                  `json:"bframes,string,omitempty"`
-	Gop                     string               `json:"gop,omitempty"`
-	Metadata                *Metadata            `json:"metadata,omitempty"`
-	Destination             []string             `json:"destination,omitempty"`
-	SegmentDuration         uint                 `json:"segment_duration,omitempty"`
-	Logo                    *Logo                `json:"logo,omitempty"`
-	Overlay                 []Overlay            `json:"overlay,omitempty"`
-	TextOverlay             []TextOverlay        `json:"text_overlay,omitempty"`
-	VideoCodecParameters    VideoCodecParameters `json:"video_codec_parameters,omitempty"`
-	Profile                 string               `json:"profile,omitempty"`
-	Rotate                  string               `json:"rotate,omitempty"`
-	SetRotate               string               `json:"set_rotate,omitempty"`
-	AudioSync               string               `json:"audio_sync,omitempty"`
-	VideoSync               string               `json:"video_sync,omitempty"`
-	ForceInterlaced         string               `json:"force_interlaced,omitempty"`
-	Stream                  []Stream             `json:"stream,omitempty"`
-	AddMeta                 YesNoBoolean         `json:"add_meta,omitempty"`
-	Hint                    YesNoBoolean         `json:"hint,omitempty"`
-	KeepAspectRatio         YesNoBoolean         `json:"keep_aspect_ratio,omitempty"`
-	StripChapters           YesNoBoolean         `json:"strip_chapters,omitempty"`
-	TwoPass                 YesNoBoolean         `json:"two_pass,omitempty"`
-	Turbo                   YesNoBoolean         `json:"turbo,omitempty"`
-	TwinTurbo               YesNoBoolean         `json:"twin_turbo,omitempty"`
-	PackFiles               *YesNoBoolean        `json:"pack_files,omitempty"`
+	Output                  []string             `json:"output,omitempty" xml:"output,omitempty"`
+	NoiseReduction          string               `json:"noise_reduction,omitempty" xml:"noise_reduction,omitempty"`
+	OutputPreset            string               `json:"output_preset,omitempty" xml:"output_preset,omitempty"`
+	VideoCodec              string               `json:"video_codec,omitempty" xml:"video_codec,omitempty"`
+	AudioCodec              string               `json:"audio_codec,omitempty" xml:"audio_codec,omitempty"`
+	Bitrate                 string               `json:"bitrate,omitempty" xml:"bitrate,omitempty"`
+	AudioBitrate            string               `json:"audio_bitrate,omitempty" xml:"audio_bitrate,omitempty"`
+	AudioChannelsNumber     string               `json:"audio_channels_number,omitempty" xml:"audio_channels_number,omitempty"`
+	Framerate               string               `json:"framerate,omitempty" xml:"framerate,omitempty"`
+	FramerateUpperThreshold string               `json:"framerate_upper_threshold,omitempty" xml:"framerate_upper_threshold,omitempty"`
+	Size                    string               `json:"size,omitempty" xml:"size,omitempty"`
+	FadeIn                  string               `json:"fade_in,omitempty" xml:"fade_in,omitempty"`
+	FadeOut                 string               `json:"fade_out,omitempty" xml:"fade_out,omitempty"`
+	AudioSampleRate         uint                 `json:"audio_sample_rate,string,omitempty" xml:"audio_sample_rate,omitempty"`
+	AudioVolume             uint                 `json:"audio_volume,string,omitempty" xml:"audio_volume,omitempty"`
+	CropLeft                int                  `json:"crop_left,string,omitempty" xml:"crop_left,omitempty"`
+	CropTop                 int                  `json:"crop_top,string,omitempty" xml:"crop_top,omitempty"`
+	CropRight               int                  `json:"crop_right,string,omitempty" xml:"crop_right,omitempty"`
+	CropBottom              int                  `json:"crop_bottom,string,omitempty" xml:"crop_bottom,omitempty"`
+	SetAspectRatio          string               `json:"set_aspect_ratio,omitempty" xml:"set_aspect_ratio,omitempty"`
+	RcInitOccupancy         string               `json:"rc_init_occupancy,omitempty" xml:"rc_init_occupancy,omitempty"`
+	MinRate                 string               `json:"minrate,omitempty" xml:"minrate,omitempty"`
+	MaxRate                 string               `json:"maxrate,omitempty" xml:"maxrate,omitempty"`
+	BufSize                 string               `json:"bufsize,omitempty" xml:"bufsize,omitempty"`
+	Keyframe                []string             `json:"keyframe,omitempty" xml:"keyframe,omitempty"`
+	Start                   string               `json:"start,omitempty" xml:"start,omitempty"`
+	Duration                string               `json:"duration,omitempty" xml:"duration,omitempty"`
+	ForceKeyframes          string               `json:"force_keyframes,omitempty" xml:"force_keyframes,omitempty"`
+	Bframes                 int                  `json:"bframes,string,omitempty" xml:"bframes,omitempty"`
+	Gop                     string               `json:"gop,omitempty" xml:"gop,omitempty"`
+	Metadata                *Metadata            `json:"metadata,omitempty" xml:"metadata,omitempty"`
+	Destination             []string             `json:"destination,omitempty" xml:"destination,omitempty"`
+	SegmentDuration         uint                 `json:"segment_duration,omitempty" xml:"segment_duration,omitempty"`
+	Logo                    *Logo                `json:"logo,omitempty" xml:"logo,omitempty"`
+	Overlay                 []Overlay            `json:"overlay,omitempty" xml:"overlay,omitempty"`
+	TextOverlay             []TextOverlay        `json:"text_overlay,omitempty" xml:"text_overlay,omitempty"`
+	VideoCodecParameters    VideoCodecParameters `json:"video_codec_parameters,omitempty" xml:"video_codec_parameters,omitempty"`
+	Profile                 string               `json:"profile,omitempty" xml:"profile,omitempty"`
+	Rotate                  string               `json:"rotate,omitempty" xml:"rotate,omitempty"`
+	SetRotate               string               `json:"set_rotate,omitempty" xml:"set_rotate,omitempty"`
+	AudioSync               string               `json:"audio_sync,omitempty" xml:"audio_sync,omitempty"`
+	VideoSync               string               `json:"video_sync,omitempty" xml:"video_sync,omitempty"`
+	ForceInterlaced         string               `json:"force_interlaced,omitempty" xml:"force_interlaced,omitempty"`
+	Stream                  []Stream             `json:"stream,omitempty" xml:"stream,omitempty"`
+	AddMeta                 YesNoBoolean         `json:"add_meta,omitempty" xml:"add_meta,omitempty"`
+	Hint                    YesNoBoolean         `json:"hint,omitempty" xml:"hint,omitempty"`
+	KeepAspectRatio         YesNoBoolean         `json:"keep_aspect_ratio,omitempty" xml:"keep_aspect_ratio,omitempty"`
+	StripChapters           YesNoBoolean         `json:"strip_chapters,omitempty" xml:"strip_chapters,omitempty"`
+	TwoPass                 YesNoBoolean         `json:"two_pass,omitempty" xml:"two_pass,omitempty"`
+	Turbo                   YesNoBoolean         `json:"turbo,omitempty" xml:"turbo,omitempty"`
+	TwinTurbo               YesNoBoolean         `json:"twin_turbo,omitempty" xml:"twin_turbo,omitempty"`
+	PackFiles               *YesNoBoolean        `json:"pack_files,omitempty" xml:"pack_files,omitempty"`
 }
 
 // Stream is the set of options for defining Advanced HLS stream output
@@ -211,47 +349,47 @@ type Format struct {
 //
 // See http://goo.gl/I7qRNo for more details.
 type Stream struct {
-	AudioBitrate            string       `json:"audio_bitrate,omitempty"`
-	AudioChannelsNumber     string       `json:"audio_channels_number,omitempty"`
-	AudioCodec              string       `json:"audio_codec,omitempty"`
-	AudioSampleRate         uint         `json:"audio_sample_rate,string,omitempty"`
-	AudioVolume             uint         `json:"audio_volume,string,omitempty"`
-	Bitrate                 string       `json:"bitrate,omitempty"`
-	Deinterlacing           string       `json:"deinterlacing,omitempty"`
-	DownmixMode             string       `json:"downmix_mode,omitempty"`
-	DurationPrecision       uint         `json:"duration_precision,string,omitempty"`
-	Encoder                 string       `json:"encoder,omitempty"`
-	EncryptionMethod        string       `json:"encryption_method,omitempty"`
-	Framerate               uint         `json:"framerate,string,omitempty"`
-	Keyframe                string       `json:"keyframe,omitempty"`
-	MediaPath               string       `json:"media_path,omitempty"`
-	PixFormat               string       `json:"pix_format,omitempty"`
-	Profile                 string       `json:"profile,omitempty"`
-	Rotate                  string       `json:"rotate,omitempty"`
-	SetRotate               string       `json:"set_rotate,omitempty"`
-	Size                    string       `json:"size,omitempty"`
-	StillImageSize          string       `json:"still_image_size,omitempty"`
-	StillImageTime          string       `json:"still_image_time,omitempty"`
-	SubPath                 string       `json:"sub_path,omitempty"`
-	VeryFast                string       `json:"veryfast,omitempty"`
-	VideoCodec              string       `json:"video_codec,omitempty"`
-	VideoSync               string       `json:"video_sync,omitempty"`
-	VideoCodecParametersRaw interface{}  `json:"video_codec_parameters,omitempty"`
-	AudioOnly               YesNoBoolean `json:"audio_only,omitempty"`
-	AddIframeStream         YesNoBoolean `json:"add_iframe_stream,omitempty"`
-	ByteRange               YesNoBoolean `json:"byte_range,omitempty"`
-	Cbr                     YesNoBoolean `json:"cbr,omitempty"`
-	CopyNielsenMetadata     YesNoBoolean `json:"copy_nielsen_metadata,omitempty"`
-	CopyTimestamps          YesNoBoolean `json:"copy_timestamps,omitempty"`
-	Encryption              YesNoBoolean `json:"encryption,omitempty"`
-	HardCbr                 YesNoBoolean `json:"hard_cbr,omitempty"`
-	Hint                    YesNoBoolean `json:"hint,omitempty"`
-	KeepAspectRatio         YesNoBoolean `json:"keep_aspect_ratio,omitempty"`
-	MetadataCopy            YesNoBoolean `json:"metadata_copy,omitempty"`
-	StillImage              YesNoBoolean `json:"still_image,omitempty"`
-	StripChapters           YesNoBoolean `json:"strip_chapters,omitempty"`
-	TwoPass                 YesNoBoolean `json:"two_pass,omitempty"`
-	VideoOnly               YesNoBoolean `json:"video_only,omitempty"`
+	AudioBitrate            string       `json:"audio_bitrate,omitempty" xml:"audio_bitrate,omitempty"`
+	AudioChannelsNumber     string       `json:"audio_channels_number,omitempty" xml:"audio_channels_number,omitempty"`
+	AudioCodec              string       `json:"audio_codec,omitempty" xml:"audio_codec,omitempty"`
+	AudioSampleRate         uint         `json:"audio_sample_rate,string,omitempty" xml:"audio_sample_rate,omitempty"`
+	AudioVolume             uint         `json:"audio_volume,string,omitempty" xml:"audio_volume,omitempty"`
+	Bitrate                 string       `json:"bitrate,omitempty" xml:"bitrate,omitempty"`
+	Deinterlacing           string       `json:"deinterlacing,omitempty" xml:"deinterlacing,omitempty"`
+	DownmixMode             string       `json:"downmix_mode,omitempty" xml:"downmix_mode,omitempty"`
+	DurationPrecision       uint         `json:"duration_precision,string,omitempty" xml:"duration_precision,omitempty"`
+	Encoder                 string       `json:"encoder,omitempty" xml:"encoder,omitempty"`
+	EncryptionMethod        string       `json:"encryption_method,omitempty" xml:"encryption_method,omitempty"`
+	Framerate               uint         `json:"framerate,string,omitempty" xml:"framerate,omitempty"`
+	Keyframe                string       `json:"keyframe,omitempty" xml:"keyframe,omitempty"`
+	MediaPath               string       `json:"media_path,omitempty" xml:"media_path,omitempty"`
+	PixFormat               string       `json:"pix_format,omitempty" xml:"pix_format,omitempty"`
+	Profile                 string       `json:"profile,omitempty" xml:"profile,omitempty"`
+	Rotate                  string       `json:"rotate,omitempty" xml:"rotate,omitempty"`
+	SetRotate               string       `json:"set_rotate,omitempty" xml:"set_rotate,omitempty"`
+	Size                    string       `json:"size,omitempty" xml:"size,omitempty"`
+	StillImageSize          string       `json:"still_image_size,omitempty" xml:"still_image_size,omitempty"`
+	StillImageTime          string       `json:"still_image_time,omitempty" xml:"still_image_time,omitempty"`
+	SubPath                 string       `json:"sub_path,omitempty" xml:"sub_path,omitempty"`
+	VeryFast                string       `json:"veryfast,omitempty" xml:"veryfast,omitempty"`
+	VideoCodec              string       `json:"video_codec,omitempty" xml:"video_codec,omitempty"`
+	VideoSync               string       `json:"video_sync,omitempty" xml:"video_sync,omitempty"`
+	VideoCodecParametersRaw interface{}  `json:"video_codec_parameters,omitempty" xml:"-"`
+	AudioOnly               YesNoBoolean `json:"audio_only,omitempty" xml:"audio_only,omitempty"`
+	AddIframeStream         YesNoBoolean `json:"add_iframe_stream,omitempty" xml:"add_iframe_stream,omitempty"`
+	ByteRange               YesNoBoolean `json:"byte_range,omitempty" xml:"byte_range,omitempty"`
+	Cbr                     YesNoBoolean `json:"cbr,omitempty" xml:"cbr,omitempty"`
+	CopyNielsenMetadata     YesNoBoolean `json:"copy_nielsen_metadata,omitempty" xml:"copy_nielsen_metadata,omitempty"`
+	CopyTimestamps          YesNoBoolean `json:"copy_timestamps,omitempty" xml:"copy_timestamps,omitempty"`
+	Encryption              YesNoBoolean `json:"encryption,omitempty" xml:"encryption,omitempty"`
+	HardCbr                 YesNoBoolean `json:"hard_cbr,omitempty" xml:"hard_cbr,omitempty"`
+	Hint                    YesNoBoolean `json:"hint,omitempty" xml:"hint,omitempty"`
+	KeepAspectRatio         YesNoBoolean `json:"keep_aspect_ratio,omitempty" xml:"keep_aspect_ratio,omitempty"`
+	MetadataCopy            YesNoBoolean `json:"metadata_copy,omitempty" xml:"metadata_copy,omitempty"`
+	StillImage              YesNoBoolean `json:"still_image,omitempty" xml:"still_image,omitempty"`
+	StripChapters           YesNoBoolean `json:"strip_chapters,omitempty" xml:"strip_chapters,omitempty"`
+	TwoPass                 YesNoBoolean `json:"two_pass,omitempty" xml:"two_pass,omitempty"`
+	VideoOnly               YesNoBoolean `json:"video_only,omitempty" xml:"video_only,omitempty"`
 }
 
 // VideoCodecParameters function returns settings for H.264 video codec.
@@ -266,25 +404,25 @@ func (s Stream) VideoCodecParameters() VideoCodecParameters {
 //
 // See http://goo.gl/8y7VSU for more details.
 type VideoCodecParameters struct {
-	Coder       string `json:"coder,omitempty"`
-	Flags       string `json:"flags,omitempty"`
-	Flags2      string `json:"flags2,omitempty"`
-	Cmp         string `json:"cmp,omitempty"`
-	Partitions  string `json:"partitions,omitempty"`
-	MeMethod    string `json:"me_method,omitempty"`
-	Subq        string `json:"subq,omitempty"`
-	MeRange     string `json:"me_range,omitempty"`
-	KeyIntMin   string `json:"keyint_min,omitempty"`
-	ScThreshold string `json:"sc_threshold,omitempty"`
-	Iqfactor    string `json:"i_qfactor,omitempty"`
-	Bstrategy   string `json:"b_strategy,omitempty"`
-	Qcomp       string `json:"qcomp,omitempty"`
-	Qmin        string `json:"qmin,omitempty"`
-	Qmax        string `json:"qmax,omitempty"`
-	Qdiff       string `json:"qdiff,omitempty"`
-	DirectPred  string `json:"directpred,omitempty"`
-	Level       string `json:"level,omitempty"`
-	Vprofile    string `json:"vprofile,omitempty"`
+	Coder       string `json:"coder,omitempty" xml:"coder,omitempty"`
+	Flags       string `json:"flags,omitempty" xml:"flags,omitempty"`
+	Flags2      string `json:"flags2,omitempty" xml:"flags2,omitempty"`
+	Cmp         string `json:"cmp,omitempty" xml:"cmp,omitempty"`
+	Partitions  string `json:"partitions,omitempty" xml:"partitions,omitempty"`
+	MeMethod    string `json:"me_method,omitempty" xml:"me_method,omitempty"`
+	Subq        string `json:"subq,omitempty" xml:"subq,omitempty"`
+	MeRange     string `json:"me_range,omitempty" xml:"me_range,omitempty"`
+	KeyIntMin   string `json:"keyint_min,omitempty" xml:"keyint_min,omitempty"`
+	ScThreshold string `json:"sc_threshold,omitempty" xml:"sc_threshold,omitempty"`
+	Iqfactor    string `json:"i_qfactor,omitempty" xml:"i_qfactor,omitempty"`
+	Bstrategy   string `json:"b_strategy,omitempty" xml:"b_strategy,omitempty"`
+	Qcomp       string `json:"qcomp,omitempty" xml:"qcomp,omitempty"`
+	Qmin        string `json:"qmin,omitempty" xml:"qmin,omitempty"`
+	Qmax        string `json:"qmax,omitempty" xml:"qmax,omitempty"`
+	Qdiff       string `json:"qdiff,omitempty" xml:"qdiff,omitempty"`
+	DirectPred  string `json:"directpred,omitempty" xml:"directpred,omitempty"`
+	Level       string `json:"level,omitempty" xml:"level,omitempty"`
+	Vprofile    string `json:"vprofile,omitempty" xml:"vprofile,omitempty"`
 }
 
 // Logo is the set of options for watermarking media during encoding, allowing
@@ -292,11 +430,11 @@ type VideoCodecParameters struct {
 //
 // See http://goo.gl/4z2Q5S for more details.
 type Logo struct {
-	LogoSourceURL string `json:"logo_source,omitempty"`
-	LogoX         int    `json:"logo_x,string,omitempty"`
-	LogoY         int    `json:"logo_y,string,omitempty"`
-	LogoMode      int    `json:"logo_mode,string,omitempty"`
-	LogoThreshold string `json:"logo_threshold,omitempty"`
+	LogoSourceURL string `json:"logo_source,omitempty" xml:"logo_source,omitempty"`
+	LogoX         int    `json:"logo_x,string,omitempty" xml:"logo_x,omitempty"`
+	LogoY         int    `json:"logo_y,string,omitempty" xml:"logo_y,omitempty"`
+	LogoMode      int    `json:"logo_mode,string,omitempty" xml:"logo_mode,omitempty"`
+	LogoThreshold string `json:"logo_threshold,omitempty" xml:"logo_threshold,omitempty"`
 }
 
 // Overlay is the set of options for adding a video overlay in the media being
@@ -304,14 +442,14 @@ type Logo struct {
 //
 // See http://goo.gl/Q6sjkR for more details.
 type Overlay struct {
-	OverlaySource   string  `json:"overlay_source,omitempty"`
-	OverlayLeft     string  `json:"overlay_left,omitempty"`
-	OverlayRight    string  `json:"overlay_right,omitempty"`
-	OverlayTop      string  `json:"overlay_top,omitempty"`
-	OverlayBottom   string  `json:"overlay_bottom"`
-	Size            string  `json:"size,omitempty"`
-	OverlayStart    float64 `json:"overlay_start,string,omitempty"`
-	OverlayDuration float64 `json:"overlay_duration,string,omitempty"`
+	OverlaySource   string  `json:"overlay_source,omitempty" xml:"overlay_source,omitempty"`
+	OverlayLeft     string  `json:"overlay_left,omitempty" xml:"overlay_left,omitempty"`
+	OverlayRight    string  `json:"overlay_right,omitempty" xml:"overlay_right,omitempty"`
+	OverlayTop      string  `json:"overlay_top,omitempty" xml:"overlay_top,omitempty"`
+	OverlayBottom   string  `json:"overlay_bottom" xml:"overlay_bottom"`
+	Size            string  `json:"size,omitempty" xml:"size,omitempty"`
+	OverlayStart    float64 `json:"overlay_start,string,omitempty" xml:"overlay_start,omitempty"`
+	OverlayDuration float64 `json:"overlay_duration,string,omitempty" xml:"overlay_duration,omitempty"`
 }
 
 // TextOverlay is the set of options for adding a text overlay in the media
@@ -319,17 +457,17 @@ type Overlay struct {
 //
 // See http://goo.gl/gUKi5t for more details.
 type TextOverlay struct {
-	Text            []string       `json:"text,omitempty"`
-	FontSourceURL   string         `json:"font_source,omitempty"`
-	FontSize        uint           `json:"font_size,string,omitempty"`
-	FontRotate      int            `json:"font_rotate,string,omitempty"`
-	FontColor       string         `json:"font_color,omitempty"`
-	AlignCenter     ZeroOneBoolean `json:"align_center,omitempty"`
-	OverlayX        int            `json:"overlay_x,string,omitempty"`
-	OverlayY        int            `json:"overlay_y,string,omitempty"`
-	Size            string         `json:"size,omitempty"`
-	OverlayStart    float64        `json:"overlay_start,string,omitempty"`
-	OverlayDuration float64        `json:"overlay_duration,string,omitempty"`
+	Text            []string       `json:"text,omitempty" xml:"text,omitempty"`
+	FontSourceURL   string         `json:"font_source,omitempty" xml:"font_source,omitempty"`
+	FontSize        uint           `json:"font_size,string,omitempty" xml:"font_size,omitempty"`
+	FontRotate      int            `json:"font_rotate,string,omitempty" xml:"font_rotate,omitempty"`
+	FontColor       string         `json:"font_color,omitempty" xml:"font_color,omitempty"`
+	AlignCenter     ZeroOneBoolean `json:"align_center,omitempty" xml:"align_center,omitempty"`
+	OverlayX        int            `json:"overlay_x,string,omitempty" xml:"overlay_x,omitempty"`
+	OverlayY        int            `json:"overlay_y,string,omitempty" xml:"overlay_y,omitempty"`
+	Size            string         `json:"size,omitempty" xml:"size,omitempty"`
+	OverlayStart    float64        `json:"overlay_start,string,omitempty" xml:"overlay_start,omitempty"`
+	OverlayDuration float64        `json:"overlay_duration,string,omitempty" xml:"overlay_duration,omitempty"`
 }
 
 // Metadata represents media metadata, as provided in the Format struct when
@@ -337,11 +475,11 @@ type TextOverlay struct {
 //
 // See http://goo.gl/jNSio9 for more details.
 type Metadata struct {
-	Title       string `json:"title,omitempty"`
-	Copyright   string `json:"copyright,omitempty"`
-	Author      string `json:"author,omitempty"`
-	Description string `json:"description,omitempty"`
-	Album       string `json:"album,omitempty"`
+	Title       string `json:"title,omitempty" xml:"title,omitempty"`
+	Copyright   string `json:"copyright,omitempty" xml:"copyright,omitempty"`
+	Author      string `json:"author,omitempty" xml:"author,omitempty"`
+	Description string `json:"description,omitempty" xml:"description,omitempty"`
+	Album       string `json:"album,omitempty" xml:"album,omitempty"`
 }
 
 // YesNoBoolean is a boolean that turns true into "yes" and false into "no"
@@ -365,6 +503,23 @@ func (b *YesNoBoolean) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalText is the method that ensures that YesNoBoolean is encoded as
+// "yes" or "no" in XML.
+func (b YesNoBoolean) MarshalText() ([]byte, error) {
+	return []byte(boolToString(bool(b), "yes", "no")), nil
+}
+
+// UnmarshalText is the method that ensures that YesNoBoolean can be converted
+// back from "yes" or "no" in XML.
+func (b *YesNoBoolean) UnmarshalText(data []byte) error {
+	v, err := stringToBool(string(data), "yes", "no")
+	if err != nil {
+		return err
+	}
+	*b = YesNoBoolean(v)
+	return nil
+}
+
 // ZeroOneBoolean is a boolean that turns true into "1" and false into "0" when
 // encoded as JSON.
 type ZeroOneBoolean bool
@@ -386,6 +541,41 @@ func (b *ZeroOneBoolean) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// MarshalText is the method that ensures that ZeroOneBoolean is encoded as
+// "1" or "0" in XML.
+func (b ZeroOneBoolean) MarshalText() ([]byte, error) {
+	return []byte(boolToString(bool(b), "1", "0")), nil
+}
+
+// UnmarshalText is the method that ensures that ZeroOneBoolean can be
+// converted back from "1" or "0" in XML.
+func (b *ZeroOneBoolean) UnmarshalText(data []byte) error {
+	v, err := stringToBool(string(data), "1", "0")
+	if err != nil {
+		return err
+	}
+	*b = ZeroOneBoolean(v)
+	return nil
+}
+
+func boolToString(b bool, t, f string) string {
+	if b {
+		return t
+	}
+	return f
+}
+
+func stringToBool(data string, t, f string) (bool, error) {
+	switch data {
+	case t:
+		return true, nil
+	case f:
+		return false, nil
+	default:
+		return false, fmt.Errorf("invalid value: %s", data)
+	}
+}
+
 func boolToBytes(b bool, t, f string) []byte {
 	if b {
 		return []byte(`"` + t + `"`)
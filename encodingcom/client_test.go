@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestNewClient(t *testing.T) {
@@ -13,6 +14,7 @@ func TestNewClient(t *testing.T) {
 		Endpoint: "https://manage.encoding.com",
 		UserID:   "myuser",
 		UserKey:  "secret-key",
+		Location: DefaultLocation,
 	}
 	got, err := NewClient("https://manage.encoding.com", "myuser", "secret-key")
 	if err != nil {
@@ -23,6 +25,31 @@ func TestNewClient(t *testing.T) {
 	}
 }
 
+func TestFallbackLocation(t *testing.T) {
+	loc := fallbackLocation()
+	var tests = []struct {
+		utc      string
+		expected string
+	}{
+		{"2016-01-15T17:00:00Z", "2016-01-15 12:00:00 -0500 EST"},
+		{"2016-07-15T16:00:00Z", "2016-07-15 12:00:00 -0400 EDT"},
+		{"2016-03-13T06:59:59Z", "2016-03-13 01:59:59 -0500 EST"},
+		{"2016-03-13T07:00:00Z", "2016-03-13 03:00:00 -0400 EDT"},
+		{"2016-11-06T05:59:59Z", "2016-11-06 01:59:59 -0400 EDT"},
+		{"2016-11-06T06:00:00Z", "2016-11-06 01:00:00 -0500 EST"},
+	}
+	system, err := time.LoadLocation(DefaultTimeZone)
+	for _, test := range tests {
+		instant, _ := time.Parse(time.RFC3339, test.utc)
+		if got := instant.In(loc).String(); got != test.expected {
+			t.Errorf("wrong time for %s\nwant %q\ngot  %q", test.utc, test.expected, got)
+		}
+		if err == nil && instant.In(system).String() != test.expected {
+			t.Errorf("fallback location doesn't match the system timezone for %s: %s", test.utc, instant.In(system))
+		}
+	}
+}
+
 func TestYesNoBooleanMarshal(t *testing.T) {
 	var tests = []struct {
 		name     string
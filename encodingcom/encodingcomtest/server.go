@@ -0,0 +1,509 @@
+// Package encodingcomtest provides a fake implementation of the Encoding.com
+// API, for testing code that depends on the encodingcom package.
+//
+// The fake server is stateful: media added with AddMedia progress over time
+// (see SetProcessingTime and Advance), can be cancelled and have their status
+// queried, and presets can be saved, listed and deleted. Errors can be
+// injected per action with SetError, and individual media can be marked as
+// failed with FailMedia.
+//
+// Only the JSON wire format is supported.
+package encodingcomtest // import "github.com/NYTimes/encoding-wrapper/encodingcom/encodingcomtest"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NYTimes/encoding-wrapper/encodingcom"
+)
+
+// DefaultProcessingTime is the time it takes for media to finish processing
+// in the fake server, unless changed with SetProcessingTime.
+const DefaultProcessingTime = 10 * time.Second
+
+// Server is a fake Encoding.com API server. It should be created with
+// NewServer and closed with Close.
+type Server struct {
+	// URL is the endpoint of the fake server, to be used when creating
+	// encodingcom clients.
+	URL string
+
+	server         *httptest.Server
+	mu             sync.Mutex
+	lastID         int
+	offset         time.Duration
+	processingTime time.Duration
+	media          map[string]*media
+	presets        map[string]encodingcom.Preset
+	errors         map[string]string
+}
+
+type media struct {
+	id         string
+	userID     string
+	source     []string
+	formats    []map[string]interface{}
+	created    time.Time
+	stoppedAt  time.Time
+	status     encodingcom.MediaStatus
+	errMessage string
+}
+
+type query struct {
+	UserID   string                   `json:"userid"`
+	UserKey  string                   `json:"userkey"`
+	Action   string                   `json:"action"`
+	MediaID  string                   `json:"mediaid"`
+	Source   []string                 `json:"source"`
+	Extended string                   `json:"extended"`
+	Type     string                   `json:"type"`
+	Name     string                   `json:"name"`
+	Format   []map[string]interface{} `json:"format"`
+}
+
+// NewServer creates and starts a new fake server.
+func NewServer() *Server {
+	s := Server{
+		processingTime: DefaultProcessingTime,
+		media:          make(map[string]*media),
+		presets:        make(map[string]encodingcom.Preset),
+		errors:         make(map[string]string),
+	}
+	s.server = httptest.NewServer(&s)
+	s.URL = s.server.URL
+	return &s
+}
+
+// Client returns an encodingcom client pointing to the fake server.
+func (s *Server) Client() *encodingcom.Client {
+	client, _ := encodingcom.NewClient(s.URL, "myuser", "secret-key")
+	return client
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// SetProcessingTime defines how long media added to the server take to
+// finish processing.
+func (s *Server) SetProcessingTime(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processingTime = d
+}
+
+// Advance moves the clock of the server forward, simulating the progress of
+// media being processed.
+func (s *Server) Advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offset += d
+}
+
+// SetError makes the server return an error with the given message for all
+// requests with the given action (e.g. "AddMedia"), until ClearError is
+// called.
+func (s *Server) SetError(action, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors[action] = message
+}
+
+// ClearError removes the error previously injected with SetError.
+func (s *Server) ClearError(action string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.errors, action)
+}
+
+// FailMedia marks the given media as failed, with the given error message.
+// It returns false if the media doesn't exist or has already finished.
+func (s *Server) FailMedia(mediaID, message string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.media[mediaID]
+	if !ok || !s.stop(m, encodingcom.MediaStatusError) {
+		return false
+	}
+	m.errMessage = message
+	return true
+}
+
+// AddPreset stores the given preset in the server, making it available in
+// preset actions. The type of the preset defaults to encodingcom.UIPresets.
+func (s *Server) AddPreset(preset encodingcom.Preset) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if preset.Type == "" {
+		preset.Type = encodingcom.UIPresets
+	}
+	s.presets[preset.Name] = preset
+}
+
+// MediaStatus returns the current status of the given media, and whether it
+// exists.
+func (s *Server) MediaStatus(mediaID string) (encodingcom.MediaStatus, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, ok := s.media[mediaID]
+	if !ok {
+		return "", false
+	}
+	status, _ := s.progress(m)
+	return status, true
+}
+
+// ServeHTTP handles requests to the fake server.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Query query `json:"query"`
+	}
+	err := json.Unmarshal([]byte(r.FormValue("json")), &body)
+	if err != nil {
+		writeError(w, "invalid json: "+err.Error())
+		return
+	}
+	q := body.Query
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if q.UserID == "" || q.UserKey == "" {
+		writeError(w, "Wrong user id or key!")
+		return
+	}
+	if message, ok := s.errors[q.Action]; ok {
+		writeError(w, message)
+		return
+	}
+	var resp interface{}
+	switch q.Action {
+	case "AddMedia":
+		resp, err = s.addMedia(q)
+	case "GetStatus":
+		resp, err = s.getStatus(q)
+	case "GetMediaInfo":
+		resp, err = s.getMediaInfo(q)
+	case "CancelMedia":
+		resp, err = s.cancelMedia(q)
+	case "GetMediaList":
+		resp, err = s.listMedia()
+	case "SavePreset":
+		resp, err = s.savePreset(q)
+	case "GetPreset":
+		resp, err = s.getPreset(q)
+	case "GetPresetsList":
+		resp, err = s.listPresets(q)
+	case "DeletePreset":
+		resp, err = s.deletePreset(q)
+	default:
+		err = fmt.Errorf("Wrong query format or action: %q", q.Action)
+	}
+	if err != nil {
+		writeError(w, err.Error())
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"response": resp})
+}
+
+func writeError(w http.ResponseWriter, message string) {
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"response": map[string]interface{}{
+			"errors": map[string]string{"error": message},
+		},
+	})
+}
+
+func (s *Server) now() time.Time {
+	return time.Now().Add(s.offset)
+}
+
+func (s *Server) addMedia(q query) (interface{}, error) {
+	if len(q.Source) == 0 {
+		return nil, errors.New("Source file is not indicated")
+	}
+	if len(q.Format) == 0 {
+		return nil, errors.New("No formats specified")
+	}
+	s.lastID++
+	id := strconv.Itoa(s.lastID)
+	s.media[id] = &media{
+		id:      id,
+		userID:  q.UserID,
+		source:  q.Source,
+		formats: q.Format,
+		created: s.now(),
+	}
+	return map[string]string{"message": "Added", "MediaID": id}, nil
+}
+
+func (s *Server) findMedia(ids string) ([]*media, error) {
+	var result []*media
+	for _, id := range strings.Split(ids, ",") {
+		m, ok := s.media[strings.TrimSpace(id)]
+		if !ok {
+			return nil, fmt.Errorf("Media ID %s not found", id)
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+// stop stops processing the given media, changing its status. It returns
+// false if the media was already in a terminal status.
+func (s *Server) stop(m *media, status encodingcom.MediaStatus) bool {
+	if current, _ := s.progress(m); current.IsTerminal() {
+		return false
+	}
+	m.stoppedAt = s.now()
+	m.status = status
+	return true
+}
+
+// progress returns the status and the progress of the given media,
+// computed based on the time since it was created.
+func (s *Server) progress(m *media) (encodingcom.MediaStatus, float64) {
+	until := s.now()
+	if m.status != "" {
+		until = m.stoppedAt
+	}
+	elapsed := until.Sub(m.created)
+	if elapsed >= s.processingTime {
+		if m.status != "" {
+			return m.status, 100
+		}
+		return encodingcom.MediaStatusFinished, 100
+	}
+	progress := float64(elapsed) / float64(s.processingTime) * 100
+	if m.status != "" {
+		return m.status, progress
+	}
+	return encodingcom.MediaStatusProcessing, progress
+}
+
+func (s *Server) getStatus(q query) (interface{}, error) {
+	medias, err := s.findMedia(q.MediaID)
+	if err != nil {
+		return nil, err
+	}
+	statuses := make([]map[string]interface{}, len(medias))
+	for i, m := range medias {
+		statuses[i] = s.mediaStatus(m)
+	}
+	var result interface{} = statuses
+	if len(statuses) == 1 {
+		result = statuses[0]
+	}
+	if q.Extended == "yes" {
+		return map[string]interface{}{"job": result}, nil
+	}
+	return result, nil
+}
+
+func (s *Server) mediaStatus(m *media) map[string]interface{} {
+	status, progress := s.progress(m)
+	timeLeft := time.Duration(float64(s.processingTime) * (100 - progress) / 100)
+	var finished time.Time
+	if status.IsTerminal() {
+		timeLeft = 0
+		finished = m.created.Add(time.Duration(float64(s.processingTime) * progress / 100))
+	}
+	taskStatus := encodingcom.TaskStatus(status)
+	destinationStatus := encodingcom.TaskStatusProcessing
+	if status == encodingcom.MediaStatusFinished {
+		destinationStatus = encodingcom.TaskStatusSaved
+	} else if status.IsTerminal() {
+		destinationStatus = taskStatus
+	}
+	formats := make([]map[string]interface{}, len(m.formats))
+	for i, format := range m.formats {
+		formatStatus := map[string]interface{}{
+			"id":       m.id + strconv.Itoa(i+1),
+			"status":   taskStatus,
+			"created":  mediaDateTime(m.created),
+			"started":  mediaDateTime(m.created),
+			"finished": mediaDateTime(finished),
+		}
+		for _, key := range []string{"output", "size", "bitrate", "video_codec", "audio_codec"} {
+			if value, ok := format[key]; ok {
+				if values, ok := value.([]interface{}); ok && len(values) > 0 {
+					value = values[0]
+				}
+				formatStatus[key] = value
+			}
+		}
+		if destinations, ok := format["destination"].([]interface{}); ok {
+			statuses := make([]interface{}, len(destinations))
+			for j := range destinations {
+				statuses[j] = destinationStatus
+			}
+			formatStatus["destination"] = destinations
+			formatStatus["destination_status"] = statuses
+		}
+		if m.errMessage != "" {
+			formatStatus["description"] = m.errMessage
+		}
+		formats[i] = formatStatus
+	}
+	return map[string]interface{}{
+		"id":                m.id,
+		"userid":            m.userID,
+		"sourcefile":        strings.Join(m.source, ","),
+		"status":            status,
+		"created":           mediaDateTime(m.created),
+		"started":           mediaDateTime(m.created),
+		"finished":          mediaDateTime(finished),
+		"downloaded":        mediaDateTime(m.created),
+		"uploaded":          mediaDateTime(finished),
+		"time_left":         strconv.Itoa(int(timeLeft.Seconds())),
+		"progress":          strconv.FormatFloat(progress, 'f', 1, 64),
+		"time_left_current": strconv.Itoa(int(timeLeft.Seconds())),
+		"progress_current":  strconv.FormatFloat(progress, 'f', 1, 64),
+		"format":            formats,
+	}
+}
+
+func mediaDateTime(t time.Time) encodingcom.MediaDateTime {
+	if t.IsZero() {
+		return encodingcom.MediaDateTime{}
+	}
+	return encodingcom.MediaDateTime{Time: t.In(encodingcom.DefaultLocation)}
+}
+
+func (s *Server) getMediaInfo(q query) (interface{}, error) {
+	medias, err := s.findMedia(q.MediaID)
+	if err != nil {
+		return nil, err
+	}
+	if status, _ := s.progress(medias[0]); status == encodingcom.MediaStatusError {
+		return nil, errors.New("Media info is not available")
+	}
+	return map[string]string{
+		"bitrate":              "1807k",
+		"duration":             "183.5",
+		"audio_bitrate":        "128k",
+		"video_codec":          "h264",
+		"video_bitrate":        "1679k",
+		"frame_rate":           "29.97",
+		"size":                 "1920x1080",
+		"pixel_aspect_ratio":   "1:1",
+		"display_aspect_ratio": "16:9",
+		"audio_codec":          "aac",
+		"audio_sample_rate":    "48000",
+		"audio_channels":       "2",
+		"rotation":             "0",
+	}, nil
+}
+
+func (s *Server) cancelMedia(q query) (interface{}, error) {
+	medias, err := s.findMedia(q.MediaID)
+	if err != nil {
+		return nil, err
+	}
+	if !s.stop(medias[0], encodingcom.MediaStatusDeleted) {
+		return nil, fmt.Errorf("Media %s can't be cancelled", q.MediaID)
+	}
+	return map[string]string{"message": "Deleted"}, nil
+}
+
+func (s *Server) listMedia() (interface{}, error) {
+	items := []map[string]interface{}{}
+	for i := 1; i <= s.lastID; i++ {
+		m, ok := s.media[strconv.Itoa(i)]
+		if !ok {
+			continue
+		}
+		status, progress := s.progress(m)
+		item := map[string]interface{}{
+			"mediafile":   strings.Join(m.source, ","),
+			"mediaid":     m.id,
+			"mediastatus": status,
+			"createdate":  mediaDateTime(m.created),
+			"startdate":   mediaDateTime(m.created),
+			"finishdate":  mediaDateTime(time.Time{}),
+		}
+		if status.IsTerminal() {
+			item["finishdate"] = mediaDateTime(m.created.Add(time.Duration(float64(s.processingTime) * progress / 100)))
+		}
+		items = append(items, item)
+	}
+	return map[string]interface{}{"media": items}, nil
+}
+
+func (s *Server) savePreset(q query) (interface{}, error) {
+	if len(q.Format) == 0 {
+		return nil, errors.New("No format specified")
+	}
+	name := q.Name
+	if name == "" {
+		name = "preset_" + strconv.Itoa(len(s.presets)+1)
+	}
+	format := make(map[string]interface{}, len(q.Format[0]))
+	for key, value := range q.Format[0] {
+		// Presets hold a single value for fields that are lists in the
+		// format used by AddMedia.
+		if values, ok := value.([]interface{}); ok && key != "stream" && len(values) > 0 {
+			value = values[0]
+		}
+		format[key] = value
+	}
+	data, _ := json.Marshal(format)
+	var presetFormat encodingcom.PresetFormat
+	err := json.Unmarshal(data, &presetFormat)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid format: %s", err)
+	}
+	s.presets[name] = encodingcom.Preset{
+		Name:   name,
+		Type:   encodingcom.UserPresets,
+		Output: presetFormat.Output,
+		Format: presetFormat,
+	}
+	return map[string]string{"SavedPreset": name}, nil
+}
+
+func (s *Server) getPreset(q query) (interface{}, error) {
+	preset, ok := s.presets[q.Name]
+	if !ok {
+		return nil, fmt.Errorf("Preset %s not found", q.Name)
+	}
+	return preset, nil
+}
+
+func (s *Server) listPresets(q query) (interface{}, error) {
+	user := []encodingcom.Preset{}
+	ui := []encodingcom.Preset{}
+	for _, preset := range s.presets {
+		if preset.Type == encodingcom.UserPresets {
+			user = append(user, preset)
+		} else {
+			ui = append(ui, preset)
+		}
+	}
+	sort.Slice(user, func(i, j int) bool { return user[i].Name < user[j].Name })
+	sort.Slice(ui, func(i, j int) bool { return ui[i].Name < ui[j].Name })
+	result := map[string]interface{}{}
+	if q.Type == "" || q.Type == string(encodingcom.AllPresets) || q.Type == string(encodingcom.UserPresets) {
+		result["user"] = user
+	}
+	if q.Type == "" || q.Type == string(encodingcom.AllPresets) || q.Type == string(encodingcom.UIPresets) {
+		result["ui"] = ui
+	}
+	return result, nil
+}
+
+func (s *Server) deletePreset(q query) (interface{}, error) {
+	preset, ok := s.presets[q.Name]
+	if !ok || preset.Type != encodingcom.UserPresets {
+		return nil, fmt.Errorf("Preset %s not found", q.Name)
+	}
+	delete(s.presets, q.Name)
+	return map[string]string{"message": "Deleted"}, nil
+}
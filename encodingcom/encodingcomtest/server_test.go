@@ -0,0 +1,222 @@
+package encodingcomtest
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/NYTimes/encoding-wrapper/encodingcom"
+)
+
+func addMedia(t *testing.T, client *encodingcom.Client) string {
+	resp, err := client.AddMedia([]string{"http://some.video/file.mov"}, []encodingcom.Format{
+		{
+			Output:      []string{"mp4"},
+			Size:        "1920x1080",
+			Bitrate:     "3500k",
+			VideoCodec:  "libx264",
+			Destination: []string{"s3://mybucket/file.mp4"},
+		},
+	}, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.MediaID
+}
+
+func TestMediaProgress(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.SetProcessingTime(time.Hour)
+	client := server.Client()
+	mediaID := addMedia(t, client)
+	if mediaID != "1" {
+		t.Errorf("wrong media id\nwant %q\ngot  %q", "1", mediaID)
+	}
+
+	server.Advance(30 * time.Minute)
+	status, err := client.GetStatus([]string{mediaID}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status[0].MediaStatus != encodingcom.MediaStatusProcessing {
+		t.Errorf("wrong status\nwant %q\ngot  %q", encodingcom.MediaStatusProcessing, status[0].MediaStatus)
+	}
+	if status[0].Progress < 50 || status[0].Progress > 51 {
+		t.Errorf("wrong progress\nwant ~50\ngot  %f", status[0].Progress)
+	}
+	if status[0].TimeLeft <= 29*time.Minute || status[0].TimeLeft > 30*time.Minute {
+		t.Errorf("wrong time left\nwant ~30m\ngot  %s", status[0].TimeLeft)
+	}
+
+	server.Advance(time.Hour)
+	status, err = client.GetStatus([]string{mediaID}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFormats := []encodingcom.FormatStatus{
+		{
+			ID:           "11",
+			Status:       encodingcom.TaskStatusFinished,
+			CreateDate:   status[0].CreateDate,
+			StartDate:    status[0].StartDate,
+			FinishDate:   status[0].FinishDate,
+			Output:       "mp4",
+			Size:         "1920x1080",
+			Bitrate:      "3500k",
+			VideoCodec:   "libx264",
+			Destinations: []encodingcom.DestinationStatus{{Name: "s3://mybucket/file.mp4", Status: encodingcom.TaskStatusSaved}},
+		},
+	}
+	if status[0].MediaStatus != encodingcom.MediaStatusFinished {
+		t.Errorf("wrong status\nwant %q\ngot  %q", encodingcom.MediaStatusFinished, status[0].MediaStatus)
+	}
+	if status[0].Progress != 100 {
+		t.Errorf("wrong progress\nwant 100\ngot  %f", status[0].Progress)
+	}
+	if !reflect.DeepEqual(status[0].Formats, expectedFormats) {
+		t.Errorf("wrong formats\nwant %#v\ngot  %#v", expectedFormats, status[0].Formats)
+	}
+	if finishedIn := status[0].FinishDate.Sub(status[0].CreateDate); finishedIn != time.Hour {
+		t.Errorf("wrong processing time\nwant %s\ngot  %s", time.Hour, finishedIn)
+	}
+}
+
+func TestCancelMedia(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	mediaID := addMedia(t, client)
+	_, err := client.CancelMedia(mediaID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Advance(time.Hour)
+	if status, _ := server.MediaStatus(mediaID); status != encodingcom.MediaStatusDeleted {
+		t.Errorf("wrong status\nwant %q\ngot  %q", encodingcom.MediaStatusDeleted, status)
+	}
+	_, err = client.CancelMedia(mediaID)
+	if err == nil {
+		t.Error("unexpected <nil> error when cancelling media twice")
+	}
+	_, err = client.CancelMedia("404")
+	if err == nil {
+		t.Error("unexpected <nil> error when cancelling unknown media")
+	}
+}
+
+func TestFailMedia(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	mediaID := addMedia(t, client)
+	if !server.FailMedia(mediaID, "failed to download the source") {
+		t.Fatal("unexpected false return from FailMedia")
+	}
+	status, err := client.GetStatus([]string{mediaID}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status[0].MediaStatus != encodingcom.MediaStatusError {
+		t.Errorf("wrong status\nwant %q\ngot  %q", encodingcom.MediaStatusError, status[0].MediaStatus)
+	}
+	if description := status[0].Formats[0].Description; description != "failed to download the source" {
+		t.Errorf("wrong description\nwant %q\ngot  %q", "failed to download the source", description)
+	}
+	if server.FailMedia("404", "not found") {
+		t.Error("unexpected true return from FailMedia for unknown media")
+	}
+}
+
+func TestGetMediaInfo(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	mediaID := addMedia(t, client)
+	info, err := client.GetMediaInfo(mediaID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size != "1920x1080" || info.Duration != 183500*time.Millisecond {
+		t.Errorf("wrong media info: %#v", info)
+	}
+}
+
+func TestSetError(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	server.SetError("AddMedia", "something went wrong")
+	_, err := client.AddMedia([]string{"http://some.video/file.mov"}, []encodingcom.Format{{Output: []string{"mp4"}}}, "")
+	expectedErr := &encodingcom.APIError{Errors: []string{"something went wrong"}}
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("wrong error\nwant %#v\ngot  %#v", expectedErr, err)
+	}
+	server.ClearError("AddMedia")
+	addMedia(t, client)
+}
+
+func TestPresets(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	server.AddPreset(encodingcom.Preset{
+		Name:   "mp4_1080p",
+		Output: "mp4",
+		Format: encodingcom.PresetFormat{Output: "mp4", Size: "1920x1080"},
+	})
+	saved, err := client.SavePreset("my_webm", encodingcom.Format{
+		Output:     []string{"webm"},
+		VideoCodec: "libvpx",
+		TwoPass:    true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.SavedPreset != "my_webm" {
+		t.Errorf("wrong saved preset\nwant %q\ngot  %q", "my_webm", saved.SavedPreset)
+	}
+
+	preset, err := client.GetPreset("my_webm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedPreset := &encodingcom.Preset{
+		Name:   "my_webm",
+		Type:   encodingcom.UserPresets,
+		Output: "webm",
+		Format: encodingcom.PresetFormat{
+			Output:               "webm",
+			VideoCodec:           "libvpx",
+			TwoPass:              true,
+			VideoCodecParameters: map[string]interface{}{},
+		},
+	}
+	if !reflect.DeepEqual(preset, expectedPreset) {
+		t.Errorf("wrong preset\nwant %#v\ngot  %#v", expectedPreset, preset)
+	}
+
+	list, err := client.ListPresets(encodingcom.AllPresets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.UserPresets) != 1 || list.UserPresets[0].Name != "my_webm" {
+		t.Errorf("wrong user presets: %#v", list.UserPresets)
+	}
+	if len(list.UIPresets) != 1 || list.UIPresets[0].Name != "mp4_1080p" {
+		t.Errorf("wrong ui presets: %#v", list.UIPresets)
+	}
+
+	_, err = client.DeletePreset("mp4_1080p")
+	if err == nil {
+		t.Error("unexpected <nil> error when deleting ui preset")
+	}
+	_, err = client.DeletePreset("my_webm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetPreset("my_webm")
+	if err == nil {
+		t.Error("unexpected <nil> error when getting deleted preset")
+	}
+}
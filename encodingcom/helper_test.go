@@ -2,6 +2,7 @@ package encodingcom
 
 import (
 	"encoding/json"
+	"encoding/xml"
 	"net/http"
 	"net/http/httptest"
 )
@@ -23,3 +24,31 @@ func startServer(content string) (*httptest.Server, chan fakeServerRequest) {
 	}))
 	return server, requests
 }
+
+type fakeXMLServerRequest struct {
+	req   *http.Request
+	query xmlQuery
+}
+
+type xmlQuery struct {
+	XMLName  xml.Name `xml:"query"`
+	UserID   string   `xml:"userid"`
+	UserKey  string   `xml:"userkey"`
+	Action   string   `xml:"action"`
+	MediaID  string   `xml:"mediaid"`
+	Source   []string `xml:"source"`
+	Extended string   `xml:"extended"`
+	Name     string   `xml:"name"`
+	Format   []Format `xml:"format"`
+}
+
+func startXMLServer(content string) (*httptest.Server, chan fakeXMLServerRequest) {
+	requests := make(chan fakeXMLServerRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var query xmlQuery
+		xml.Unmarshal([]byte(r.FormValue("xml")), &query)
+		requests <- fakeXMLServerRequest{req: r, query: query}
+		w.Write([]byte(content))
+	}))
+	return server, requests
+}
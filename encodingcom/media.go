@@ -1,21 +1,42 @@
 package encodingcom
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // dateTimeLayout is the time layout used on Media items
 const dateTimeLayout = "2006-01-02 15:04:05"
 
-// MediaDateTime is a custom time struct to be used on Media items
+// zeroDateTime is the value used by the API to represent empty dates
+const zeroDateTime = "0000-00-00 00:00:00"
+
+// MediaDateTime is a custom time struct to be used on Media items.
+//
+// The Encoding.com API returns dates without timezone information, so
+// UnmarshalJSON stores them in UTC. Methods in Client convert the values to
+// the proper instant using the Location of the client.
 type MediaDateTime struct {
 	time.Time
 }
 
+// MarshalJSON implementation on MediaDateTime to use dateTimeLayout. The
+// date is formatted in its own location, so dates returned by the Client are
+// formatted in the same timezone used by the API.
+func (mdt MediaDateTime) MarshalJSON() ([]byte, error) {
+	if mdt.IsZero() {
+		return []byte(`"` + zeroDateTime + `"`), nil
+	}
+	return []byte(`"` + mdt.Format(dateTimeLayout) + `"`), nil
+}
+
 // UnmarshalJSON implementation on MediaDateTime to use dateTimeLayout
 func (mdt *MediaDateTime) UnmarshalJSON(b []byte) (err error) {
 	if b[0] == '"' && b[len(b)-1] == '"' {
 		b = b[1 : len(b)-1]
 	}
-	if string(b) == "0000-00-00 00:00:00" {
+	if string(b) == zeroDateTime {
 		mdt.Time = time.Time{}
 		return nil
 	}
@@ -23,39 +44,82 @@ func (mdt *MediaDateTime) UnmarshalJSON(b []byte) (err error) {
 	return err
 }
 
+// MarshalText implementation on MediaDateTime to use dateTimeLayout in XML.
+func (mdt MediaDateTime) MarshalText() ([]byte, error) {
+	if mdt.IsZero() {
+		return []byte(zeroDateTime), nil
+	}
+	return []byte(mdt.Format(dateTimeLayout)), nil
+}
+
+// UnmarshalText implementation on MediaDateTime to use dateTimeLayout in XML.
+func (mdt *MediaDateTime) UnmarshalText(b []byte) (err error) {
+	value := strings.TrimSpace(string(b))
+	if value == "" || value == zeroDateTime {
+		mdt.Time = time.Time{}
+		return nil
+	}
+	mdt.Time, err = time.Parse(dateTimeLayout, value)
+	return err
+}
+
+// inLocation returns a MediaDateTime with the same wall clock in the given
+// location.
+func (mdt MediaDateTime) inLocation(loc *time.Location) MediaDateTime {
+	if mdt.IsZero() {
+		return mdt
+	}
+	t := mdt.Time
+	return MediaDateTime{time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), loc)}
+}
+
 // AddMediaResponse represents the response returned by the AddMedia action.
 //
 // See http://goo.gl/Aqg8lc for more details.
 type AddMediaResponse struct {
-	Message string `json:"message,omitempty"`
-	MediaID string `json:"mediaid,omitempty"`
+	Message string `json:"message,omitempty" xml:"message,omitempty"`
+	MediaID string `json:"mediaid,omitempty" xml:"MediaID,omitempty"`
 }
 
 // ListMediaResponse represents the response returned by the GetMediaList action.
 //
 // See http://goo.gl/xhVV6v for more details.
 type ListMediaResponse struct {
-	Media []ListMediaResponseItem `json:"media,omitempty"`
+	Media []ListMediaResponseItem `json:"media,omitempty" xml:"media,omitempty"`
 }
 
 // ListMediaResponseItem represents each individual item returned by the GetMediaList action.
 //
 // See ListMediaResponse
 type ListMediaResponseItem struct {
-	MediaFile   string        `json:"mediafile,omitempty"`
-	MediaID     string        `json:"mediaid,omitempty"`
-	MediaStatus string        `json:"mediastatus,omitempty"`
-	CreateDate  MediaDateTime `json:"createdate,string,omitempty"`
-	StartDate   MediaDateTime `json:"startdate,string,omitempty"`
-	FinishDate  MediaDateTime `json:"finishdate,string,omitempty"`
+	MediaFile   string        `json:"mediafile,omitempty" xml:"mediafile,omitempty"`
+	MediaID     string        `json:"mediaid,omitempty" xml:"mediaid,omitempty"`
+	MediaStatus MediaStatus   `json:"mediastatus,omitempty" xml:"mediastatus,omitempty"`
+	CreateDate  MediaDateTime `json:"createdate,string,omitempty" xml:"createdate,omitempty"`
+	StartDate   MediaDateTime `json:"startdate,string,omitempty" xml:"startdate,omitempty"`
+	FinishDate  MediaDateTime `json:"finishdate,string,omitempty" xml:"finishdate,omitempty"`
 }
 
 // AddMedia adds a new media to user's queue.
 //
 // Format specifies details on how the source files are going to be encoded.
 //
+// When the client has ValidateFormats set, formats are validated before
+// sending the request (see Format.Validate), and an error of type
+// ValidationErrors is returned when any of them is invalid. Fields in the
+// error are prefixed by the index of the format (e.g. "format[0].size").
+//
 // See http://goo.gl/whvHwJ for more details on the source file formatting.
 func (c *Client) AddMedia(source []string, format []Format, region string) (*AddMediaResponse, error) {
+	if c.ValidateFormats {
+		var errs ValidationErrors
+		for i, f := range format {
+			errs.merge(fmt.Sprintf("format[%d].", i), f.Validate())
+		}
+		if err := errs.err(); err != nil {
+			return nil, err
+		}
+	}
 	var result map[string]*AddMediaResponse
 	req := request{
 		Action: "AddMedia",
@@ -111,31 +175,40 @@ func (c *Client) ListMedia() (*ListMediaResponse, error) {
 	if err != nil {
 		return nil, err
 	}
-	return result["response"], nil
+	resp := result["response"]
+	if resp != nil {
+		loc := c.location()
+		for i, item := range resp.Media {
+			resp.Media[i].CreateDate = item.CreateDate.inLocation(loc)
+			resp.Media[i].StartDate = item.StartDate.inLocation(loc)
+			resp.Media[i].FinishDate = item.FinishDate.inLocation(loc)
+		}
+	}
+	return resp, nil
 }
 
 // MediaInfo is the result of the GetMediaInfo method.
 //
 // See http://goo.gl/OTX0Ua for more details.
 type MediaInfo struct {
-	Duration           time.Duration
-	Bitrate            string `json:"bitrate"`
-	VideoCodec         string `json:"video_codec"`
-	VideoBitrate       string `json:"video_bitrate"`
-	Framerate          string `json:"frame_rate"`
-	Size               string `json:"size"`
-	PixelAspectRatio   string `json:"pixel_aspect_ratio"`
-	DisplayAspectRatio string `json:"display_aspect_ratio"`
-	AudioCodec         string `json:"audio_codec"`
-	AudioBitrate       string `json:"audio_bitrate"`
-	AudioSampleRate    uint   `json:"audio_sample_rate,string"`
-	AudioChannels      string `json:"audio_channels"`
-	Rotation           uint   `json:"rotation,string"`
+	Duration           time.Duration `xml:"-"`
+	Bitrate            string        `json:"bitrate" xml:"bitrate"`
+	VideoCodec         string        `json:"video_codec" xml:"video_codec"`
+	VideoBitrate       string        `json:"video_bitrate" xml:"video_bitrate"`
+	Framerate          string        `json:"frame_rate" xml:"frame_rate"`
+	Size               string        `json:"size" xml:"size"`
+	PixelAspectRatio   string        `json:"pixel_aspect_ratio" xml:"pixel_aspect_ratio"`
+	DisplayAspectRatio string        `json:"display_aspect_ratio" xml:"display_aspect_ratio"`
+	AudioCodec         string        `json:"audio_codec" xml:"audio_codec"`
+	AudioBitrate       string        `json:"audio_bitrate" xml:"audio_bitrate"`
+	AudioSampleRate    uint          `json:"audio_sample_rate,string" xml:"audio_sample_rate"`
+	AudioChannels      string        `json:"audio_channels" xml:"audio_channels"`
+	Rotation           uint          `json:"rotation,string" xml:"rotation"`
 }
 
 type mediaInfo struct {
 	MediaInfo
-	Duration float64 `json:"duration,string"`
+	Duration float64 `json:"duration,string" xml:"duration"`
 }
 
 // GetMediaInfo returns video parameters of the specified media when available.
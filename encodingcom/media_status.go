@@ -3,28 +3,41 @@ package encodingcom
 import (
 	"encoding/json"
 	"errors"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// UnknownTimeLeft is the value of the TimeLeft and TimeLeftCurrentJob fields
+// in StatusResponse when the Encoding.com API doesn't provide an estimate.
+const UnknownTimeLeft = time.Duration(-1)
+
+// maxTimeLeftSeconds is the largest number of seconds that fits in a
+// time.Duration.
+const maxTimeLeftSeconds = float64(math.MaxInt64 / int64(time.Second))
+
 // StatusResponse is the result of the GetStatus method.
 //
+// TimeLeft and TimeLeftCurrentJob are set to UnknownTimeLeft when the API
+// doesn't return a valid estimate.
+//
 // See http://goo.gl/NDsN8h for more details.
 type StatusResponse struct {
 	MediaID             string
 	UserID              string
 	SourceFile          string
-	MediaStatus         string
-	PreviousMediaStatus string
+	MediaStatus         MediaStatus
+	PreviousMediaStatus MediaStatus
 	NotifyURL           string
 	CreateDate          time.Time
 	StartDate           time.Time
 	FinishDate          time.Time
 	DownloadDate        time.Time
 	UploadDate          time.Time
-	TimeLeft            string
+	TimeLeft            time.Duration
 	Progress            float64
-	TimeLeftCurrentJob  string
+	TimeLeftCurrentJob  time.Duration
 	ProgressCurrentJob  float64
 	Formats             []FormatStatus
 }
@@ -34,7 +47,7 @@ type StatusResponse struct {
 // It is part of the StatusResponse type.
 type FormatStatus struct {
 	ID            string
-	Status        string
+	Status        TaskStatus
 	CreateDate    time.Time
 	StartDate     time.Time
 	FinishDate    time.Time
@@ -51,10 +64,85 @@ type FormatStatus struct {
 	FileSize      string
 }
 
+// GetFileSize returns the size of the output file in bytes, or 0 when it's
+// not available.
+func (f *FormatStatus) GetFileSize() int64 {
+	size, err := strconv.ParseInt(strings.TrimSpace(f.FileSize), 10, 64)
+	if err != nil || size < 0 {
+		return 0
+	}
+	return size
+}
+
+// GetBitrate parses the underlying bitrate and converts it to bits per
+// second, or 0 when it's not available (including negative, non-finite and
+// out of range values).
+//
+// Examples:
+//  - Input: "3500k"
+//    Output: 3500000
+//  - Input: "1.5M"
+//    Output: 1500000
+//  - Input: "128000"
+//    Output: 128000
+func (f *FormatStatus) GetBitrate() int64 {
+	value := strings.ToLower(strings.TrimSpace(f.Bitrate))
+	value = strings.TrimSuffix(strings.TrimSuffix(value, "bps"), "b/s")
+	value = strings.TrimSpace(value)
+	multiplier := float64(1)
+	if n := len(value); n > 0 {
+		switch value[n-1] {
+		case 'k':
+			multiplier = 1e3
+		case 'm':
+			multiplier = 1e6
+		case 'g':
+			multiplier = 1e9
+		}
+		if multiplier != 1 {
+			value = strings.TrimSpace(value[:n-1])
+		}
+	}
+	bitrate, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(bitrate) || math.IsInf(bitrate, 0) || bitrate < 0 {
+		return 0
+	}
+	bitrate *= multiplier
+	// float64(math.MaxInt64) rounds up to 2^63, which doesn't fit in int64.
+	if bitrate >= math.MaxInt64 {
+		return 0
+	}
+	return int64(bitrate)
+}
+
+// GetWidth returns the width in the underlying size (in the format WxH), or 0
+// when it's not available.
+func (f *FormatStatus) GetWidth() int64 {
+	width, _ := f.dimensions()
+	return width
+}
+
+// GetHeight returns the height in the underlying size (in the format WxH), or
+// 0 when it's not available.
+func (f *FormatStatus) GetHeight() int64 {
+	_, height := f.dimensions()
+	return height
+}
+
+func (f *FormatStatus) dimensions() (int64, int64) {
+	parts := strings.SplitN(strings.ToLower(f.Size), "x", 2)
+	if len(parts) != 2 {
+		return 0, 0
+	}
+	width, _ := strconv.ParseInt(strings.TrimSpace(parts[0]), 10, 64)
+	height, _ := strconv.ParseInt(strings.TrimSpace(parts[1]), 10, 64)
+	return width, height
+}
+
 // DestinationStatus represents the status of a given destination.
 type DestinationStatus struct {
 	Name   string
-	Status string
+	Status TaskStatus
 }
 
 // GetStatus returns the status of the given media ids, it returns an slice of
@@ -65,17 +153,31 @@ func (c *Client) GetStatus(mediaIDs []string, extended bool) ([]StatusResponse,
 		return nil, errors.New("please provide at least one media id")
 	}
 
-	var m map[string]map[string]interface{}
-	err := c.do(&request{
+	req := request{
 		Action:   "GetStatus",
 		MediaID:  strings.Join(mediaIDs, ","),
 		Extended: YesNoBoolean(extended),
-	}, &m)
+	}
+	var apiStatus []statusJSON
+	if c.WireFormat == XMLWireFormat {
+		var result statusXML
+		err := c.do(&req, &result)
+		if err != nil {
+			return nil, err
+		}
+		apiStatus = result.Jobs
+		if len(apiStatus) == 0 {
+			apiStatus = append(apiStatus, result.statusJSON)
+		}
+		return c.toStatusResponse(apiStatus, (*statusJSON).xmlFormats), nil
+	}
+
+	var m map[string]map[string]interface{}
+	err := c.do(&req, &m)
 	if err != nil {
 		return nil, err
 	}
 
-	var apiStatus []statusJSON
 	var rawData interface{}
 	if extended {
 		rawData = m["response"]["job"]
@@ -90,56 +192,63 @@ func (c *Client) GetStatus(mediaIDs []string, extended bool) ([]StatusResponse,
 		json.Unmarshal(rawBytes, &item)
 		apiStatus = append(apiStatus, item)
 	}
+	return c.toStatusResponse(apiStatus, (*statusJSON).jsonFormats), nil
+}
 
+// toStatusResponse converts the statuses returned by the API, using the given
+// function to extract the formats, as they're represented differently in
+// JSON and XML responses.
+func (c *Client) toStatusResponse(apiStatus []statusJSON, formats func(*statusJSON) []formatStatusJSON) []StatusResponse {
 	statusResponse := make([]StatusResponse, len(apiStatus))
-	for i, status := range apiStatus {
-		statusResponse[i] = status.toStruct()
+	for i := range apiStatus {
+		statusResponse[i] = apiStatus[i].toStruct(c.location(), formats(&apiStatus[i]))
 	}
-	return statusResponse, nil
+	return statusResponse
+}
+
+// statusXML is the response of the GetStatus action in XML, where extended
+// responses contain one job element per media, and non-extended responses
+// contain the status directly in the response element.
+type statusXML struct {
+	statusJSON
+	Jobs []statusJSON `xml:"job"`
 }
 
 type statusJSON struct {
-	MediaID             string        `json:"id"`
-	UserID              string        `json:"userid"`
-	SourceFile          string        `json:"sourcefile"`
-	MediaStatus         string        `json:"status"`
-	PreviousMediaStatus string        `json:"prevstatus"`
-	NotifyURL           string        `json:"notifyurl"`
-	CreateDate          MediaDateTime `json:"created"`
-	StartDate           MediaDateTime `json:"started"`
-	FinishDate          MediaDateTime `json:"finished"`
-	DownloadDate        MediaDateTime `json:"downloaded"`
-	UploadDate          MediaDateTime `json:"uploaded"`
-	TimeLeft            string        `json:"time_left"`
-	Progress            float64       `json:"progress,string"`
-	TimeLeftCurrentJob  string        `json:"time_left_current"`
-	ProgressCurrentJob  float64       `json:"progress_current,string"`
-	Formats             interface{}   `json:"format"`
-}
-
-func (s *statusJSON) toStruct() StatusResponse {
-	resp := StatusResponse{
-		MediaID:             s.MediaID,
-		UserID:              s.UserID,
-		SourceFile:          s.SourceFile,
-		MediaStatus:         s.MediaStatus,
-		PreviousMediaStatus: s.PreviousMediaStatus,
-		NotifyURL:           s.NotifyURL,
-		CreateDate:          s.CreateDate.Time,
-		StartDate:           s.StartDate.Time,
-		FinishDate:          s.FinishDate.Time,
-		DownloadDate:        s.DownloadDate.Time,
-		UploadDate:          s.UploadDate.Time,
-		TimeLeft:            s.TimeLeft,
-		Progress:            s.Progress,
-		TimeLeftCurrentJob:  s.TimeLeftCurrentJob,
-		ProgressCurrentJob:  s.ProgressCurrentJob,
-	}
+	MediaID             string        `json:"id" xml:"id"`
+	UserID              string        `json:"userid" xml:"userid"`
+	SourceFile          string        `json:"sourcefile" xml:"sourcefile"`
+	MediaStatus         MediaStatus   `json:"status" xml:"status"`
+	PreviousMediaStatus MediaStatus   `json:"prevstatus" xml:"prevstatus"`
+	NotifyURL           string        `json:"notifyurl" xml:"notifyurl"`
+	CreateDate          MediaDateTime `json:"created" xml:"created"`
+	StartDate           MediaDateTime `json:"started" xml:"started"`
+	FinishDate          MediaDateTime `json:"finished" xml:"finished"`
+	DownloadDate        MediaDateTime `json:"downloaded" xml:"downloaded"`
+	UploadDate          MediaDateTime `json:"uploaded" xml:"uploaded"`
+	TimeLeft            string        `json:"time_left" xml:"time_left"`
+	Progress            float64       `json:"progress,string" xml:"progress"`
+	TimeLeftCurrentJob  string        `json:"time_left_current" xml:"time_left_current"`
+	ProgressCurrentJob  float64       `json:"progress_current,string" xml:"progress_current"`
+	Formats             interface{}   `json:"format" xml:"-"`
 
-	// Yes, Encoding.com API is nuts, and when there's a single item in the
-	// list, it returns an object instead of a list with a single item, so
-	// we marshal it back, and then unmarshal in the proper type. The same
-	// happens in the internal list of destinations.
+	// FormatsXML holds the formats when the response is in XML, where
+	// lists are represented by repeated elements.
+	FormatsXML []formatStatusJSON `json:"-" xml:"format"`
+}
+
+// xmlFormats returns the formats in a XML response.
+func (s *statusJSON) xmlFormats() []formatStatusJSON {
+	return s.FormatsXML
+}
+
+// jsonFormats returns the formats in a JSON response.
+//
+// Yes, Encoding.com API is nuts, and when there's a single item in the
+// list, it returns an object instead of a list with a single item, so
+// we marshal it back, and then unmarshal in the proper type. The same
+// happens in the internal list of destinations.
+func (s *statusJSON) jsonFormats() []formatStatusJSON {
 	var formats []formatStatusJSON
 	data, _ := json.Marshal(s.Formats)
 	if _, ok := s.Formats.([]interface{}); ok {
@@ -149,15 +258,36 @@ func (s *statusJSON) toStruct() StatusResponse {
 		json.Unmarshal(data, &formatStatus)
 		formats = append(formats, formatStatus)
 	}
+	return formats
+}
+
+func (s *statusJSON) toStruct(loc *time.Location, formats []formatStatusJSON) StatusResponse {
+	resp := StatusResponse{
+		MediaID:             s.MediaID,
+		UserID:              s.UserID,
+		SourceFile:          s.SourceFile,
+		MediaStatus:         s.MediaStatus,
+		PreviousMediaStatus: s.PreviousMediaStatus,
+		NotifyURL:           s.NotifyURL,
+		CreateDate:          s.CreateDate.inLocation(loc).Time,
+		StartDate:           s.StartDate.inLocation(loc).Time,
+		FinishDate:          s.FinishDate.inLocation(loc).Time,
+		DownloadDate:        s.DownloadDate.inLocation(loc).Time,
+		UploadDate:          s.UploadDate.inLocation(loc).Time,
+		TimeLeft:            parseTimeLeft(s.TimeLeft),
+		Progress:            s.Progress,
+		TimeLeftCurrentJob:  parseTimeLeft(s.TimeLeftCurrentJob),
+		ProgressCurrentJob:  s.ProgressCurrentJob,
+	}
 
 	resp.Formats = make([]FormatStatus, len(formats))
 	for i, formatStatus := range formats {
 		format := FormatStatus{
 			ID:            formatStatus.ID,
 			Status:        formatStatus.Status,
-			CreateDate:    formatStatus.CreateDate.Time,
-			StartDate:     formatStatus.StartDate.Time,
-			FinishDate:    formatStatus.FinishDate.Time,
+			CreateDate:    formatStatus.CreateDate.inLocation(loc).Time,
+			StartDate:     formatStatus.StartDate.inLocation(loc).Time,
+			FinishDate:    formatStatus.FinishDate.inLocation(loc).Time,
 			Description:   formatStatus.Description,
 			S3Destination: formatStatus.S3Destination,
 			CFDestination: formatStatus.CFDestination,
@@ -170,11 +300,19 @@ func (s *statusJSON) toStruct() StatusResponse {
 			FileSize:      formatStatus.FileSize,
 		}
 
+		for i, destName := range formatStatus.DestinationsXML {
+			destinationStatus := DestinationStatus{Name: destName}
+			if i < len(formatStatus.DestinationsStatusXML) {
+				destinationStatus.Status = parseTaskStatus(formatStatus.DestinationsStatusXML[i])
+			}
+			format.Destinations = append(format.Destinations, destinationStatus)
+		}
+
 		switch dest := formatStatus.Destinations.(type) {
 		case string:
 			destinationStatus := DestinationStatus{Name: dest}
 			if statusStr, ok := formatStatus.DestinationsStatus.(string); ok {
-				destinationStatus.Status = statusStr
+				destinationStatus.Status = parseTaskStatus(statusStr)
 			}
 			format.Destinations = append(format.Destinations, destinationStatus)
 		case []interface{}:
@@ -189,7 +327,7 @@ func (s *statusJSON) toStruct() StatusResponse {
 					format.Destinations[i].Name = destName
 				}
 				if statusStr, ok := destStats[i].(string); ok {
-					format.Destinations[i].Status = statusStr
+					format.Destinations[i].Status = parseTaskStatus(statusStr)
 				}
 			}
 		}
@@ -199,22 +337,38 @@ func (s *statusJSON) toStruct() StatusResponse {
 	return resp
 }
 
+// parseTimeLeft converts the number of seconds returned by the API to
+// time.Duration, returning UnknownTimeLeft for empty, negative, non-finite,
+// out of range or invalid values.
+func parseTimeLeft(value string) time.Duration {
+	seconds, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil || math.IsNaN(seconds) || math.IsInf(seconds, 0) || seconds < 0 || seconds > maxTimeLeftSeconds {
+		return UnknownTimeLeft
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
 type formatStatusJSON struct {
-	ID                 string        `json:"id"`
-	Status             string        `json:"status"`
-	CreateDate         MediaDateTime `json:"created"`
-	StartDate          MediaDateTime `json:"started"`
-	FinishDate         MediaDateTime `json:"finished"`
-	Description        string        `json:"description"`
-	S3Destination      string        `json:"s3_destination"`
-	CFDestination      string        `json:"cf_destination"`
-	Destinations       interface{}   `json:"destination"`
-	DestinationsStatus interface{}   `json:"destination_status"`
-	Size               string        `json:"size"`
-	Bitrate            string        `json:"bitrate"`
-	AudioCodec         string        `json:"audio_codec"`
-	VideoCodec         string        `json:"video_codec"`
-	Output             string        `json:"output"`
-	Stream             []Stream      `json:"stream"`
-	FileSize           string        `json:"convertedsize"`
+	ID                 string        `json:"id" xml:"id"`
+	Status             TaskStatus    `json:"status" xml:"status"`
+	CreateDate         MediaDateTime `json:"created" xml:"created"`
+	StartDate          MediaDateTime `json:"started" xml:"started"`
+	FinishDate         MediaDateTime `json:"finished" xml:"finished"`
+	Description        string        `json:"description" xml:"description"`
+	S3Destination      string        `json:"s3_destination" xml:"s3_destination"`
+	CFDestination      string        `json:"cf_destination" xml:"cf_destination"`
+	Destinations       interface{}   `json:"destination" xml:"-"`
+	DestinationsStatus interface{}   `json:"destination_status" xml:"-"`
+	Size               string        `json:"size" xml:"size"`
+	Bitrate            string        `json:"bitrate" xml:"bitrate"`
+	AudioCodec         string        `json:"audio_codec" xml:"audio_codec"`
+	VideoCodec         string        `json:"video_codec" xml:"video_codec"`
+	Output             string        `json:"output" xml:"output"`
+	Stream             []Stream      `json:"stream" xml:"stream"`
+	FileSize           string        `json:"convertedsize" xml:"convertedsize"`
+
+	// DestinationsXML and DestinationsStatusXML hold the destinations
+	// when the response is in XML.
+	DestinationsXML       []string `json:"-" xml:"destination"`
+	DestinationsStatusXML []string `json:"-" xml:"destination_status"`
 }
@@ -50,11 +50,11 @@ func TestGetStatusSingle(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	expectedCreateDate, _ := time.Parse(dateTimeLayout, "2015-12-31 20:45:30")
-	expectedStartDate, _ := time.Parse(dateTimeLayout, "2015-12-31 20:45:34")
-	expectedFinishDate, _ := time.Parse(dateTimeLayout, "2015-12-31 21:00:03")
-	expectedDownloadDate, _ := time.Parse(dateTimeLayout, "2015-12-31 20:45:32")
-	expectedUploadDate, _ := time.Parse(dateTimeLayout, "2015-12-31 20:59:54")
+	expectedCreateDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 20:45:30", DefaultLocation)
+	expectedStartDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 20:45:34", DefaultLocation)
+	expectedFinishDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 21:00:03", DefaultLocation)
+	expectedDownloadDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 20:45:32", DefaultLocation)
+	expectedUploadDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 20:59:54", DefaultLocation)
 	expected := []StatusResponse{
 		{
 			MediaID:             "abc123",
@@ -68,9 +68,9 @@ func TestGetStatusSingle(t *testing.T) {
 			FinishDate:          expectedFinishDate,
 			DownloadDate:        expectedDownloadDate,
 			UploadDate:          expectedUploadDate,
-			TimeLeft:            "0",
+			TimeLeft:            0,
 			Progress:            100.0,
-			TimeLeftCurrentJob:  "0",
+			TimeLeftCurrentJob:  0,
 			ProgressCurrentJob:  100.0,
 			Formats: []FormatStatus{
 				{
@@ -208,11 +208,11 @@ func TestGetStatusMultiple(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	expectedCreateDate, _ := time.Parse(dateTimeLayout, "2015-12-31 20:45:30")
-	expectedStartDate, _ := time.Parse(dateTimeLayout, "2015-12-31 20:45:34")
-	expectedFinishDate, _ := time.Parse(dateTimeLayout, "2015-12-31 21:00:03")
-	expectedDownloadDate, _ := time.Parse(dateTimeLayout, "2015-12-31 20:45:32")
-	expectedUploadDate, _ := time.Parse(dateTimeLayout, "2015-12-31 20:59:54")
+	expectedCreateDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 20:45:30", DefaultLocation)
+	expectedStartDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 20:45:34", DefaultLocation)
+	expectedFinishDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 21:00:03", DefaultLocation)
+	expectedDownloadDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 20:45:32", DefaultLocation)
+	expectedUploadDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 20:59:54", DefaultLocation)
 	expected := []StatusResponse{
 		{
 			MediaID:             "abc123",
@@ -226,9 +226,9 @@ func TestGetStatusMultiple(t *testing.T) {
 			FinishDate:          expectedFinishDate,
 			DownloadDate:        expectedDownloadDate,
 			UploadDate:          expectedUploadDate,
-			TimeLeft:            "0",
+			TimeLeft:            0,
 			Progress:            100.0,
-			TimeLeftCurrentJob:  "0",
+			TimeLeftCurrentJob:  0,
 			ProgressCurrentJob:  100.0,
 			Formats: []FormatStatus{
 				{
@@ -273,9 +273,9 @@ func TestGetStatusMultiple(t *testing.T) {
 			FinishDate:          expectedFinishDate,
 			DownloadDate:        expectedDownloadDate,
 			UploadDate:          expectedUploadDate,
-			TimeLeft:            "0",
+			TimeLeft:            0,
 			Progress:            100.0,
-			TimeLeftCurrentJob:  "0",
+			TimeLeftCurrentJob:  0,
 			ProgressCurrentJob:  100.0,
 			Formats: []FormatStatus{
 				{
@@ -323,22 +323,23 @@ func TestGetStatusNotExtended(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	expectedCreateDate, _ := time.Parse(dateTimeLayout, "2015-12-31 20:45:30")
-	expectedStartDate, _ := time.Parse(dateTimeLayout, "2015-12-31 20:45:34")
-	expectedFinishDate, _ := time.Parse(dateTimeLayout, "2015-12-31 21:00:03")
-	expectedDownloadDate, _ := time.Parse(dateTimeLayout, "2015-12-31 20:45:32")
+	expectedCreateDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 20:45:30", DefaultLocation)
+	expectedStartDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 20:45:34", DefaultLocation)
+	expectedFinishDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 21:00:03", DefaultLocation)
+	expectedDownloadDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 20:45:32", DefaultLocation)
 	expected := []StatusResponse{
 		{
-			MediaID:      "abc123",
-			UserID:       "myuser",
-			SourceFile:   "http://some.video/file.mp4",
-			MediaStatus:  "Finished",
-			CreateDate:   expectedCreateDate,
-			StartDate:    expectedStartDate,
-			FinishDate:   expectedFinishDate,
-			DownloadDate: expectedDownloadDate,
-			TimeLeft:     "21",
-			Progress:     100.0,
+			MediaID:            "abc123",
+			UserID:             "myuser",
+			SourceFile:         "http://some.video/file.mp4",
+			MediaStatus:        "Finished",
+			CreateDate:         expectedCreateDate,
+			StartDate:          expectedStartDate,
+			FinishDate:         expectedFinishDate,
+			DownloadDate:       expectedDownloadDate,
+			TimeLeft:           21 * time.Second,
+			Progress:           100.0,
+			TimeLeftCurrentJob: UnknownTimeLeft,
 			Formats: []FormatStatus{
 				{
 					ID:           "f123",
@@ -422,7 +423,7 @@ func TestGetStatusZeroTime(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	expectedCreateDate, _ := time.Parse(dateTimeLayout, "2016-01-29 19:32:32")
+	expectedCreateDate, _ := time.ParseInLocation(dateTimeLayout, "2016-01-29 19:32:32", DefaultLocation)
 	if status[0].MediaID != mediaID {
 		t.Errorf("wrong media id returned\nwant %q\ngot  %q", mediaID, status[0].MediaID)
 	}
@@ -465,3 +466,114 @@ func TestGetStatusError(t *testing.T) {
 		t.Errorf("unexpected non-nil response: %#v", resp)
 	}
 }
+
+func TestParseTimeLeft(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected time.Duration
+	}{
+		{"0", 0},
+		{"21", 21 * time.Second},
+		{"1.5", 1500 * time.Millisecond},
+		{" 3600 ", time.Hour},
+		{"", UnknownTimeLeft},
+		{"-1", UnknownTimeLeft},
+		{"unknown", UnknownTimeLeft},
+		{"NaN", UnknownTimeLeft},
+		{"Inf", UnknownTimeLeft},
+		{"-Inf", UnknownTimeLeft},
+		{"1e300", UnknownTimeLeft},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.input, func(t *testing.T) {
+			if got := parseTimeLeft(test.input); got != test.expected {
+				t.Errorf("wrong time left\nwant %s\ngot  %s", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatStatusFileSize(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected int64
+	}{
+		{"78544430", 78544430},
+		{" 65723 ", 65723},
+		{"", 0},
+		{"-1", 0},
+		{"whatever", 0},
+		{"NaN", 0},
+		{"Inf", 0},
+		{"1e30", 0},
+		{"123.5", 0},
+		{"9223372036854775808", 0},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.input, func(t *testing.T) {
+			format := FormatStatus{FileSize: test.input}
+			if got := format.GetFileSize(); got != test.expected {
+				t.Errorf("wrong file size\nwant %d\ngot  %d", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatStatusBitrate(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected int64
+	}{
+		{"3500k", 3500000},
+		{"3500K", 3500000},
+		{"1.5M", 1500000},
+		{"128 kbps", 128000},
+		{"128000", 128000},
+		{"", 0},
+		{"whatever", 0},
+		{"-128k", 0},
+		{"NaN", 0},
+		{"Inf", 0},
+		{"-Infk", 0},
+		{"1e30", 0},
+		{"9.3e9G", 0},
+		{"9e9G", 9000000000000000000},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.input, func(t *testing.T) {
+			format := FormatStatus{Bitrate: test.input}
+			if got := format.GetBitrate(); got != test.expected {
+				t.Errorf("wrong bitrate\nwant %d\ngot  %d", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestFormatStatusDimensions(t *testing.T) {
+	var tests = []struct {
+		input          string
+		expectedWidth  int64
+		expectedHeight int64
+	}{
+		{"1920x1080", 1920, 1080},
+		{"0x1080", 0, 1080},
+		{"1280X720", 1280, 720},
+		{"", 0, 0},
+		{"whatever", 0, 0},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.input, func(t *testing.T) {
+			format := FormatStatus{Size: test.input}
+			if got := format.GetWidth(); got != test.expectedWidth {
+				t.Errorf("wrong width\nwant %d\ngot  %d", test.expectedWidth, got)
+			}
+			if got := format.GetHeight(); got != test.expectedHeight {
+				t.Errorf("wrong height\nwant %d\ngot  %d", test.expectedHeight, got)
+			}
+		})
+	}
+}
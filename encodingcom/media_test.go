@@ -1,6 +1,7 @@
 package encodingcom
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 	"time"
@@ -274,9 +275,9 @@ func TestListMedia(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	mockCreateDate, _ := time.Parse(dateTimeLayout, "2015-12-31 20:45:30")
-	mockStartDate, _ := time.Parse(dateTimeLayout, "2015-12-31 20:45:50")
-	mockFinishDate, _ := time.Parse(dateTimeLayout, "2015-12-31 20:48:54")
+	mockCreateDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 20:45:30", DefaultLocation)
+	mockStartDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 20:45:50", DefaultLocation)
+	mockFinishDate, _ := time.ParseInLocation(dateTimeLayout, "2015-12-31 20:48:54", DefaultLocation)
 
 	expectedResp := &ListMediaResponse{
 		Media: []ListMediaResponseItem{
@@ -385,3 +386,116 @@ func TestGetMediaInfoError(t *testing.T) {
 		t.Errorf("unexpected non-nil response: %#v", resp)
 	}
 }
+
+func TestListMediaCustomLocation(t *testing.T) {
+	server, _ := startServer(`
+{
+    "response":{
+        "media":[
+            {
+                "mediafile":"http://another.non.existent/video.mp4",
+                "mediaid":"1234567",
+                "mediastatus":"Finished",
+                "createdate":"2015-12-31 20:45:30",
+                "startdate":"2015-12-31 20:45:50",
+                "finishdate":"0000-00-00 00:00:00"
+            }
+        ]
+    }
+}`)
+	defer server.Close()
+
+	loc := time.FixedZone("PST", -8*60*60)
+	client := Client{Endpoint: server.URL, UserID: "myuser", UserKey: "123", Location: loc}
+	listMediaResponse, err := client.ListMedia()
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := listMediaResponse.Media[0]
+	expectedCreateDate := time.Date(2016, time.January, 1, 4, 45, 30, 0, time.UTC)
+	if !item.CreateDate.Equal(expectedCreateDate) {
+		t.Errorf("wrong create date\nwant %s\ngot  %s", expectedCreateDate, item.CreateDate.UTC())
+	}
+	if item.CreateDate.Location() != loc {
+		t.Errorf("wrong location\nwant %s\ngot  %s", loc, item.CreateDate.Location())
+	}
+	if !item.FinishDate.IsZero() {
+		t.Errorf("unexpected non-zero finish date: %s", item.FinishDate)
+	}
+}
+
+func TestMediaDateTimeMarshalJSON(t *testing.T) {
+	var tests = []struct {
+		name     string
+		input    MediaDateTime
+		expected string
+	}{
+		{
+			"in default location",
+			MediaDateTime{time.Date(2015, time.December, 31, 20, 45, 30, 0, DefaultLocation)},
+			`"2015-12-31 20:45:30"`,
+		},
+		{
+			"in UTC",
+			MediaDateTime{time.Date(2015, time.December, 31, 20, 45, 30, 0, time.UTC)},
+			`"2015-12-31 20:45:30"`,
+		},
+		{
+			"zero",
+			MediaDateTime{},
+			`"0000-00-00 00:00:00"`,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			data, err := json.Marshal(test.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != test.expected {
+				t.Errorf("wrong JSON\nwant %s\ngot  %s", test.expected, data)
+			}
+			var roundTrip MediaDateTime
+			err = json.Unmarshal(data, &roundTrip)
+			if err != nil {
+				t.Fatal(err)
+			}
+			roundTrip = roundTrip.inLocation(test.input.Location())
+			if !roundTrip.Equal(test.input.Time) {
+				t.Errorf("wrong date after round trip\nwant %s\ngot  %s", test.input, roundTrip)
+			}
+		})
+	}
+}
+
+func TestAddMediaValidateFormats(t *testing.T) {
+	server, requests := startServer(`{"response": {"message": "Added", "MediaID": "1234567"}}`)
+	defer server.Close()
+
+	client := Client{Endpoint: server.URL, UserID: "myuser", UserKey: "123", ValidateFormats: true}
+	formats := []Format{
+		{Output: []string{"mp4"}, VideoCodec: "libx264"},
+		{Output: []string{"webm"}, Size: "1080p"},
+	}
+	addMediaResponse, err := client.AddMedia([]string{"http://another.non.existent/video.mov"}, formats, "us-east-1")
+	expected := ValidationErrors{{Field: "format[1].size", Message: `invalid size "1080p", expected <width>x<height>`}}
+	if !reflect.DeepEqual(err, expected) {
+		t.Errorf("wrong error\nwant %#v\ngot  %#v", expected, err)
+	}
+	if addMediaResponse != nil {
+		t.Errorf("unexpected non-nil response: %#v", addMediaResponse)
+	}
+	select {
+	case req := <-requests:
+		t.Errorf("unexpected request sent to encoding.com: %#v", req)
+	default:
+	}
+
+	formats[1].Size = "1920x1080"
+	_, err = client.AddMedia([]string{"http://another.non.existent/video.mov"}, formats, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-requests
+}
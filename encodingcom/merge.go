@@ -0,0 +1,156 @@
+package encodingcom
+
+import "reflect"
+
+var streamSliceType = reflect.TypeOf([]Stream(nil))
+
+// MergeFormat returns the result of applying the given override on top of
+// the base format, so users can express things like "the base format, but
+// with a different bitrate and destination". Neither of the given formats is
+// modified, and the result doesn't share memory with them.
+//
+// Precedence is defined field by field: fields set (non-zero) in the override
+// replace the ones in the base format, and unset fields keep the base value.
+// Nested values are merged deeply:
+//
+//   - Logo, Metadata and VideoCodecParameters are merged field by field;
+//   - Streams are merged by position, so the first stream in the override is
+//     merged into the first stream of the base format, and so on. Zero-valued
+//     streams in the override keep the base stream untouched, and extra
+//     streams are appended;
+//   - raw video codec parameters in streams are merged key by key when both
+//     values are objects.
+//
+// Other slices (e.g. Output, Destination, Keyframe and Overlay) are replaced
+// as a whole. Since unset fields can't be told apart from zero values, an
+// override can't reset a field to its zero value (e.g. turn TwoPass off).
+func MergeFormat(base, override Format) Format {
+	merged := deepCopy(reflect.ValueOf(base))
+	mergeStruct(merged, reflect.ValueOf(override))
+	return merged.Interface().(Format)
+}
+
+// Merge converts the preset to a Format and applies the given override on top
+// of it (see PresetFormat.Format and MergeFormat). The output of the preset is
+// used when its format doesn't include one.
+func (p Preset) Merge(override Format) (Format, error) {
+	base, err := p.Format.Format()
+	if err != nil {
+		return Format{}, err
+	}
+	if len(base.Output) == 0 && p.Output != "" {
+		base.Output = []string{p.Output}
+	}
+	return MergeFormat(base, override), nil
+}
+
+func mergeStruct(dst, src reflect.Value) {
+	for i := 0; i < src.NumField(); i++ {
+		mergeValue(dst.Field(i), src.Field(i))
+	}
+}
+
+func mergeValue(dst, src reflect.Value) {
+	if isZeroValue(src) {
+		return
+	}
+	switch src.Kind() {
+	case reflect.Struct:
+		mergeStruct(dst, src)
+	case reflect.Ptr:
+		if src.Elem().Kind() == reflect.Struct && !dst.IsNil() {
+			mergeStruct(dst.Elem(), src.Elem())
+		} else {
+			dst.Set(deepCopy(src))
+		}
+	case reflect.Slice:
+		if src.Type() != streamSliceType {
+			dst.Set(deepCopy(src))
+			return
+		}
+		length := dst.Len()
+		if src.Len() > length {
+			length = src.Len()
+		}
+		merged := reflect.MakeSlice(src.Type(), length, length)
+		reflect.Copy(merged, dst)
+		for i := 0; i < src.Len(); i++ {
+			mergeValue(merged.Index(i), src.Index(i))
+		}
+		dst.Set(merged)
+	case reflect.Interface:
+		dst.Set(reflect.ValueOf(mergeRawValue(dst.Interface(), deepCopy(src).Interface())))
+	default:
+		dst.Set(deepCopy(src))
+	}
+}
+
+// deepCopy returns a copy of the given value that doesn't share memory with
+// it, allocating new pointers, slices and maps (including the ones in raw
+// JSON values). The returned value is addressable.
+func deepCopy(v reflect.Value) reflect.Value {
+	copied := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			copied.Set(reflect.New(v.Type().Elem()))
+			copied.Elem().Set(deepCopy(v.Elem()))
+		}
+	case reflect.Struct:
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if copied.Field(i).CanSet() {
+				copied.Field(i).Set(deepCopy(v.Field(i)))
+			}
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			copied.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+			for i := 0; i < v.Len(); i++ {
+				copied.Index(i).Set(deepCopy(v.Index(i)))
+			}
+		}
+	case reflect.Map:
+		if !v.IsNil() {
+			copied.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+			for _, key := range v.MapKeys() {
+				copied.SetMapIndex(key, deepCopy(v.MapIndex(key)))
+			}
+		}
+	case reflect.Interface:
+		if !v.IsNil() {
+			copied.Set(deepCopy(v.Elem()))
+		}
+	default:
+		copied.Set(v)
+	}
+	return copied
+}
+
+// mergeRawValue merges raw JSON values, key by key when both values are
+// objects. In any other case, the override replaces the base value.
+func mergeRawValue(base, override interface{}) interface{} {
+	baseMap, ok := base.(map[string]interface{})
+	if !ok {
+		return override
+	}
+	overrideMap, ok := override.(map[string]interface{})
+	if !ok {
+		return override
+	}
+	merged := make(map[string]interface{}, len(baseMap)+len(overrideMap))
+	for key, value := range baseMap {
+		merged[key] = value
+	}
+	for key, value := range overrideMap {
+		merged[key] = mergeRawValue(merged[key], value)
+	}
+	return merged
+}
+
+func isZeroValue(v reflect.Value) bool {
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Map {
+		return v.Len() == 0
+	}
+	return reflect.DeepEqual(v.Interface(), reflect.Zero(v.Type()).Interface())
+}
@@ -0,0 +1,184 @@
+package encodingcom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeFormat(t *testing.T) {
+	twoPass := YesNoBoolean(true)
+	noPackFiles := YesNoBoolean(false)
+	var tests = []struct {
+		testCase string
+		base     Format
+		override Format
+		expected Format
+	}{
+		{
+			"empty override",
+			Format{Output: []string{"mp4"}, Bitrate: "1000k", TwoPass: twoPass},
+			Format{},
+			Format{Output: []string{"mp4"}, Bitrate: "1000k", TwoPass: twoPass},
+		},
+		{
+			"top level fields",
+			Format{Output: []string{"mp4"}, Bitrate: "1000k", Size: "1280x720", CropTop: 10},
+			Format{Bitrate: "2000k", Destination: []string{"s3://bucket/video.mp4"}, CropTop: 20},
+			Format{Output: []string{"mp4"}, Bitrate: "2000k", Size: "1280x720", CropTop: 20, Destination: []string{"s3://bucket/video.mp4"}},
+		},
+		{
+			"slices are replaced",
+			Format{Output: []string{"mp4"}, Keyframe: []string{"90", "300"}, Overlay: []Overlay{{OverlaySource: "a.mov"}}},
+			Format{Keyframe: []string{"60"}, Overlay: []Overlay{{OverlaySource: "b.mov"}}},
+			Format{Output: []string{"mp4"}, Keyframe: []string{"60"}, Overlay: []Overlay{{OverlaySource: "b.mov"}}},
+		},
+		{
+			"nested structs",
+			Format{
+				Logo:                 &Logo{LogoSourceURL: "http://example.com/logo.png", LogoX: 10, LogoY: 10},
+				VideoCodecParameters: VideoCodecParameters{Coder: "0", Level: "31", Vprofile: "high"},
+			},
+			Format{
+				Logo:                 &Logo{LogoX: 20},
+				Metadata:             &Metadata{Title: "My video"},
+				VideoCodecParameters: VideoCodecParameters{Level: "40"},
+			},
+			Format{
+				Logo:                 &Logo{LogoSourceURL: "http://example.com/logo.png", LogoX: 20, LogoY: 10},
+				Metadata:             &Metadata{Title: "My video"},
+				VideoCodecParameters: VideoCodecParameters{Coder: "0", Level: "40", Vprofile: "high"},
+			},
+		},
+		{
+			"streams",
+			Format{Stream: []Stream{
+				{Size: "1920x1080", Bitrate: "5000k", VideoCodecParametersRaw: map[string]interface{}{"level": "40", "coder": "0"}},
+				{Size: "1280x720", Bitrate: "2500k"},
+			}},
+			Format{Stream: []Stream{
+				{Bitrate: "6000k", VideoCodecParametersRaw: map[string]interface{}{"level": "41"}},
+				{},
+				{Size: "640x360", Bitrate: "800k"},
+			}},
+			Format{Stream: []Stream{
+				{Size: "1920x1080", Bitrate: "6000k", VideoCodecParametersRaw: map[string]interface{}{"level": "41", "coder": "0"}},
+				{Size: "1280x720", Bitrate: "2500k"},
+				{Size: "640x360", Bitrate: "800k"},
+			}},
+		},
+		{
+			"raw video codec parameters replacing non-object value",
+			Format{Stream: []Stream{{VideoCodecParametersRaw: "no"}}},
+			Format{Stream: []Stream{{VideoCodecParametersRaw: map[string]interface{}{"level": "41"}}}},
+			Format{Stream: []Stream{{VideoCodecParametersRaw: map[string]interface{}{"level": "41"}}}},
+		},
+		{
+			"non-struct pointers",
+			Format{PackFiles: &twoPass},
+			Format{PackFiles: &noPackFiles},
+			Format{PackFiles: &noPackFiles},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.testCase, func(t *testing.T) {
+			got := MergeFormat(test.base, test.override)
+			if !reflect.DeepEqual(got, test.expected) {
+				t.Errorf("wrong merged format\nwant %#v\ngot  %#v", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestMergeFormatDoesNotModifyInputs(t *testing.T) {
+	packFiles := YesNoBoolean(true)
+	base := Format{
+		Destination: []string{"s3://bucket/output/"},
+		Logo:        &Logo{LogoX: 10},
+		Metadata:    &Metadata{Title: "base"},
+		Stream: []Stream{
+			{Size: "1920x1080", VideoCodecParametersRaw: map[string]interface{}{"level": "40"}},
+			{Size: "1280x720", VideoCodecParametersRaw: map[string]interface{}{"profile": map[string]interface{}{"name": "high"}}},
+		},
+	}
+	override := Format{
+		Keyframe:  []string{"90"},
+		Logo:      &Logo{LogoY: 20},
+		Stream:    []Stream{{Bitrate: "5000k", VideoCodecParametersRaw: map[string]interface{}{"coder": "0"}}},
+		PackFiles: &packFiles,
+	}
+	merged := MergeFormat(base, override)
+	merged.Destination[0] = "s3://other/"
+	merged.Keyframe[0] = "300"
+	merged.Logo.LogoMode = 1
+	merged.Metadata.Title = "merged"
+	merged.Stream[0].Size = "640x360"
+	merged.Stream[0].VideoCodecParametersRaw.(map[string]interface{})["coder"] = "1"
+	merged.Stream[1].VideoCodecParametersRaw.(map[string]interface{})["profile"].(map[string]interface{})["name"] = "main"
+	*merged.PackFiles = false
+
+	merged = MergeFormat(base, Format{Bitrate: "1k"})
+	merged.Logo.LogoX = 99
+	merged.Metadata.Title = "merged"
+	merged.Destination[0] = "z"
+	merged.Stream[0].VideoCodecParametersRaw.(map[string]interface{})["level"] = "30"
+
+	expectedBase := Format{
+		Destination: []string{"s3://bucket/output/"},
+		Logo:        &Logo{LogoX: 10},
+		Metadata:    &Metadata{Title: "base"},
+		Stream: []Stream{
+			{Size: "1920x1080", VideoCodecParametersRaw: map[string]interface{}{"level": "40"}},
+			{Size: "1280x720", VideoCodecParametersRaw: map[string]interface{}{"profile": map[string]interface{}{"name": "high"}}},
+		},
+	}
+	if !reflect.DeepEqual(base, expectedBase) {
+		t.Errorf("base format was modified\nwant %#v\ngot  %#v", expectedBase, base)
+	}
+	expectedPackFiles := YesNoBoolean(true)
+	expectedOverride := Format{
+		Keyframe:  []string{"90"},
+		Logo:      &Logo{LogoY: 20},
+		Stream:    []Stream{{Bitrate: "5000k", VideoCodecParametersRaw: map[string]interface{}{"coder": "0"}}},
+		PackFiles: &expectedPackFiles,
+	}
+	if !reflect.DeepEqual(override, expectedOverride) {
+		t.Errorf("override format was modified\nwant %#v\ngot  %#v", expectedOverride, override)
+	}
+}
+
+func TestPresetMerge(t *testing.T) {
+	preset := Preset{
+		Name:   "hls",
+		Output: "advanced_hls",
+		Format: PresetFormat{
+			SegmentDuration: "6",
+			StreamRawMap:    map[string]interface{}{"size": "1280x720", "bitrate": "2000k"},
+		},
+	}
+	override := Format{
+		Destination: []string{"s3://bucket/output/"},
+		Stream:      []Stream{{Bitrate: "2500k"}},
+	}
+	expected := Format{
+		Output:          []string{"advanced_hls"},
+		SegmentDuration: 6,
+		Destination:     []string{"s3://bucket/output/"},
+		Stream:          []Stream{{Size: "1280x720", Bitrate: "2500k"}},
+	}
+	format, err := preset.Merge(override)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(format, expected) {
+		t.Errorf("wrong merged format\nwant %#v\ngot  %#v", expected, format)
+	}
+}
+
+func TestPresetMergeError(t *testing.T) {
+	preset := Preset{Name: "hls", Format: PresetFormat{SegmentDuration: "six"}}
+	_, err := preset.Merge(Format{Bitrate: "1000k"})
+	if err == nil {
+		t.Error("unexpected <nil> error")
+	}
+}
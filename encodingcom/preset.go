@@ -1,6 +1,12 @@
 package encodingcom
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
 
 const (
 	// AllPresets is used to retrieve all presets in the response of
@@ -22,62 +28,62 @@ type PresetType string
 
 // Preset represents a preset in the Encoding.com API.
 type Preset struct {
-	Name   string       `json:"name"`
-	Type   PresetType   `json:"type"`
-	Output string       `json:"output"`
-	Format PresetFormat `json:"format"`
+	Name   string       `json:"name" xml:"name"`
+	Type   PresetType   `json:"type" xml:"type"`
+	Output string       `json:"output" xml:"output"`
+	Format PresetFormat `json:"format" xml:"format"`
 }
 
 // PresetFormat is the set of options for defining the output format in
 // presets.
 type PresetFormat struct {
-	NoiseReduction          string       `json:"noise_reduction,omitempty"`
-	Output                  string       `json:"output,omitempty"`
-	VideoCodec              string       `json:"video_codec,omitempty"`
-	AudioCodec              string       `json:"audio_codec,omitempty"`
-	Bitrate                 string       `json:"bitrate,omitempty"`
-	AudioBitrate            string       `json:"audio_bitrate,omitempty"`
-	AudioSampleRate         uint         `json:"audio_sample_rate,string,omitempty"`
-	AudioChannelsNumber     string       `json:"audio_channels_number,omitempty"`
-	AudioVolume             uint         `json:"audio_volume,string,omitempty"`
-	Framerate               string       `json:"framerate,omitempty"`
-	FramerateUpperThreshold string       `json:"framerate_upper_threshold,omitempty"`
-	Size                    string       `json:"size,omitempty"`
-	FadeIn                  string       `json:"fade_in,omitempty"`
-	FadeOut                 string       `json:"fade_out,omitempty"`
-	CropLeft                int          `json:"crop_left,string,omitempty"`
-	CropTop                 int          `json:"crop_top,string,omitempty"`
-	CropRight               int          `json:"crop_right,string,omitempty"`
-	CropBottom              int          `json:"crop_bottom,string,omitempty"`
-	SetAspectRatio          string       `json:"set_aspect_ratio,omitempty"`
-	RcInitOccupancy         string       `json:"rc_init_occupancy,omitempty"`
-	MinRate                 string       `json:"minrate,omitempty"`
-	MaxRate                 string       `json:"maxrate,omitempty"`
-	BufSize                 string       `json:"bufsize,omitempty"`
-	Keyframe                string       `json:"keyframe,omitempty"`
-	Start                   string       `json:"start,omitempty"`
-	Duration                string       `json:"duration,omitempty"`
-	ForceKeyframes          string       `json:"force_keyframes,omitempty"`
-	Bframes                 int          `json:"bframes,string,omitempty"`
-	Gop                     string       `json:"gop,omitempty"`
-	Metadata                *Metadata    `json:"metadata,omitempty"`
-	SegmentDuration         string       `json:"segment_duration,omitempty"`
-	Logo                    *Logo        `json:"logo,omitempty"`
-	VideoCodecParameters    interface{}  `json:"video_codec_parameters,omitempty"`
-	Profile                 string       `json:"profile,omitempty"`
-	Rotate                  string       `json:"rotate,omitempty"`
-	SetRotate               string       `json:"set_rotate,omitempty"`
-	AudioSync               string       `json:"audio_sync,omitempty"`
-	VideoSync               string       `json:"video_sync,omitempty"`
-	ForceInterlaced         string       `json:"force_interlaced,omitempty"`
-	KeepAspectRatio         YesNoBoolean `json:"keep_aspect_ratio,omitempty"`
-	AddMeta                 YesNoBoolean `json:"add_meta,omitempty"`
-	Hint                    YesNoBoolean `json:"hint,omitempty"`
-	TwoPass                 YesNoBoolean `json:"two_pass,omitempty"`
-	Turbo                   YesNoBoolean `json:"turbo,omitempty"`
-	TwinTurbo               YesNoBoolean `json:"twin_turbo,omitempty"`
-	StripChapters           YesNoBoolean `json:"strip_chapters,omitempty"`
-	StreamRawMap            interface{}  `json:"stream,omitempty"`
+	NoiseReduction          string       `json:"noise_reduction,omitempty" xml:"noise_reduction,omitempty"`
+	Output                  string       `json:"output,omitempty" xml:"output,omitempty"`
+	VideoCodec              string       `json:"video_codec,omitempty" xml:"video_codec,omitempty"`
+	AudioCodec              string       `json:"audio_codec,omitempty" xml:"audio_codec,omitempty"`
+	Bitrate                 string       `json:"bitrate,omitempty" xml:"bitrate,omitempty"`
+	AudioBitrate            string       `json:"audio_bitrate,omitempty" xml:"audio_bitrate,omitempty"`
+	AudioSampleRate         uint         `json:"audio_sample_rate,string,omitempty" xml:"audio_sample_rate,omitempty"`
+	AudioChannelsNumber     string       `json:"audio_channels_number,omitempty" xml:"audio_channels_number,omitempty"`
+	AudioVolume             uint         `json:"audio_volume,string,omitempty" xml:"audio_volume,omitempty"`
+	Framerate               string       `json:"framerate,omitempty" xml:"framerate,omitempty"`
+	FramerateUpperThreshold string       `json:"framerate_upper_threshold,omitempty" xml:"framerate_upper_threshold,omitempty"`
+	Size                    string       `json:"size,omitempty" xml:"size,omitempty"`
+	FadeIn                  string       `json:"fade_in,omitempty" xml:"fade_in,omitempty"`
+	FadeOut                 string       `json:"fade_out,omitempty" xml:"fade_out,omitempty"`
+	CropLeft                int          `json:"crop_left,string,omitempty" xml:"crop_left,omitempty"`
+	CropTop                 int          `json:"crop_top,string,omitempty" xml:"crop_top,omitempty"`
+	CropRight               int          `json:"crop_right,string,omitempty" xml:"crop_right,omitempty"`
+	CropBottom              int          `json:"crop_bottom,string,omitempty" xml:"crop_bottom,omitempty"`
+	SetAspectRatio          string       `json:"set_aspect_ratio,omitempty" xml:"set_aspect_ratio,omitempty"`
+	RcInitOccupancy         string       `json:"rc_init_occupancy,omitempty" xml:"rc_init_occupancy,omitempty"`
+	MinRate                 string       `json:"minrate,omitempty" xml:"minrate,omitempty"`
+	MaxRate                 string       `json:"maxrate,omitempty" xml:"maxrate,omitempty"`
+	BufSize                 string       `json:"bufsize,omitempty" xml:"bufsize,omitempty"`
+	Keyframe                string       `json:"keyframe,omitempty" xml:"keyframe,omitempty"`
+	Start                   string       `json:"start,omitempty" xml:"start,omitempty"`
+	Duration                string       `json:"duration,omitempty" xml:"duration,omitempty"`
+	ForceKeyframes          string       `json:"force_keyframes,omitempty" xml:"force_keyframes,omitempty"`
+	Bframes                 int          `json:"bframes,string,omitempty" xml:"bframes,omitempty"`
+	Gop                     string       `json:"gop,omitempty" xml:"gop,omitempty"`
+	Metadata                *Metadata    `json:"metadata,omitempty" xml:"metadata,omitempty"`
+	SegmentDuration         string       `json:"segment_duration,omitempty" xml:"segment_duration,omitempty"`
+	Logo                    *Logo        `json:"logo,omitempty" xml:"logo,omitempty"`
+	VideoCodecParameters    interface{}  `json:"video_codec_parameters,omitempty" xml:"-"`
+	Profile                 string       `json:"profile,omitempty" xml:"profile,omitempty"`
+	Rotate                  string       `json:"rotate,omitempty" xml:"rotate,omitempty"`
+	SetRotate               string       `json:"set_rotate,omitempty" xml:"set_rotate,omitempty"`
+	AudioSync               string       `json:"audio_sync,omitempty" xml:"audio_sync,omitempty"`
+	VideoSync               string       `json:"video_sync,omitempty" xml:"video_sync,omitempty"`
+	ForceInterlaced         string       `json:"force_interlaced,omitempty" xml:"force_interlaced,omitempty"`
+	KeepAspectRatio         YesNoBoolean `json:"keep_aspect_ratio,omitempty" xml:"keep_aspect_ratio,omitempty"`
+	AddMeta                 YesNoBoolean `json:"add_meta,omitempty" xml:"add_meta,omitempty"`
+	Hint                    YesNoBoolean `json:"hint,omitempty" xml:"hint,omitempty"`
+	TwoPass                 YesNoBoolean `json:"two_pass,omitempty" xml:"two_pass,omitempty"`
+	Turbo                   YesNoBoolean `json:"turbo,omitempty" xml:"turbo,omitempty"`
+	TwinTurbo               YesNoBoolean `json:"twin_turbo,omitempty" xml:"twin_turbo,omitempty"`
+	StripChapters           YesNoBoolean `json:"strip_chapters,omitempty" xml:"strip_chapters,omitempty"`
+	StreamRawMap            interface{}  `json:"stream,omitempty" xml:"-"`
 }
 
 // Stream function returns a slice of Advanced HLS stream settings for a
@@ -96,6 +102,227 @@ func (p PresetFormat) Stream() []Stream {
 	return streams
 }
 
+// Format converts the preset format to a Format, that can be used in
+// AddMedia. Fields are converted without loss, so a preset returned by
+// GetPreset can be submitted directly, after applying any overrides:
+//
+//	preset, err := client.GetPreset("webm_1080p")
+//	...
+//	format, err := preset.Format.Format()
+//	...
+//	format.Destination = []string{"s3://bucket/output.webm"}
+//	resp, err := client.AddMedia([]string{source}, []Format{format}, "us-east-1")
+//
+// An error is returned when the raw fields hold values that can't be
+// represented in the Format (e.g. unknown video codec parameters).
+func (p PresetFormat) Format() (Format, error) {
+	format := Format{
+		NoiseReduction:          p.NoiseReduction,
+		VideoCodec:              p.VideoCodec,
+		AudioCodec:              p.AudioCodec,
+		Bitrate:                 p.Bitrate,
+		AudioBitrate:            p.AudioBitrate,
+		AudioSampleRate:         p.AudioSampleRate,
+		AudioChannelsNumber:     p.AudioChannelsNumber,
+		AudioVolume:             p.AudioVolume,
+		Framerate:               p.Framerate,
+		FramerateUpperThreshold: p.FramerateUpperThreshold,
+		Size:                    p.Size,
+		FadeIn:                  p.FadeIn,
+		FadeOut:                 p.FadeOut,
+		CropLeft:                p.CropLeft,
+		CropTop:                 p.CropTop,
+		CropRight:               p.CropRight,
+		CropBottom:              p.CropBottom,
+		SetAspectRatio:          p.SetAspectRatio,
+		RcInitOccupancy:         p.RcInitOccupancy,
+		MinRate:                 p.MinRate,
+		MaxRate:                 p.MaxRate,
+		BufSize:                 p.BufSize,
+		Start:                   p.Start,
+		Duration:                p.Duration,
+		ForceKeyframes:          p.ForceKeyframes,
+		Bframes:                 p.Bframes,
+		Gop:                     p.Gop,
+		Metadata:                p.Metadata,
+		Logo:                    p.Logo,
+		Profile:                 p.Profile,
+		Rotate:                  p.Rotate,
+		SetRotate:               p.SetRotate,
+		AudioSync:               p.AudioSync,
+		VideoSync:               p.VideoSync,
+		ForceInterlaced:         p.ForceInterlaced,
+		KeepAspectRatio:         p.KeepAspectRatio,
+		AddMeta:                 p.AddMeta,
+		Hint:                    p.Hint,
+		TwoPass:                 p.TwoPass,
+		Turbo:                   p.Turbo,
+		TwinTurbo:               p.TwinTurbo,
+		StripChapters:           p.StripChapters,
+	}
+	if p.Output != "" {
+		format.Output = []string{p.Output}
+	}
+	if p.Keyframe != "" {
+		format.Keyframe = strings.Split(p.Keyframe, ",")
+	}
+	if p.SegmentDuration != "" {
+		duration, err := strconv.ParseUint(p.SegmentDuration, 10, 0)
+		if err != nil {
+			return Format{}, fmt.Errorf("invalid segment_duration %q: %s", p.SegmentDuration, err)
+		}
+		format.SegmentDuration = uint(duration)
+	}
+	if !isEmptyRawValue(p.VideoCodecParameters) {
+		err := decodeRawValue(p.VideoCodecParameters, &format.VideoCodecParameters)
+		if err != nil {
+			return Format{}, fmt.Errorf("invalid video_codec_parameters: %s", err)
+		}
+	}
+	if p.StreamRawMap != nil {
+		// The API returns a single object instead of a list when the preset
+		// has only one stream.
+		data, _ := json.Marshal(p.StreamRawMap)
+		if bytes.HasPrefix(data, []byte("{")) {
+			format.Stream = make([]Stream, 1)
+			err := decodeRawValue(p.StreamRawMap, &format.Stream[0])
+			if err != nil {
+				return Format{}, fmt.Errorf("invalid stream: %s", err)
+			}
+		} else if err := decodeRawValue(p.StreamRawMap, &format.Stream); err != nil {
+			return Format{}, fmt.Errorf("invalid stream: %s", err)
+		}
+	}
+	return format, nil
+}
+
+// NewPresetFormat converts the given Format to a PresetFormat, the inverse of
+// PresetFormat.Format. Single streams are stored as an object, matching the
+// representation returned by the API.
+//
+// An error is returned when the format uses fields that can't be stored in
+// presets (output_preset, destination, overlay, text_overlay and
+// pack_files), more than one output, or keyframes containing commas.
+func NewPresetFormat(format Format) (PresetFormat, error) {
+	var unsupported []string
+	if len(format.Output) > 1 {
+		unsupported = append(unsupported, "output (multiple values)")
+	}
+	if format.OutputPreset != "" {
+		unsupported = append(unsupported, "output_preset")
+	}
+	if len(format.Destination) > 0 {
+		unsupported = append(unsupported, "destination")
+	}
+	if len(format.Overlay) > 0 {
+		unsupported = append(unsupported, "overlay")
+	}
+	if len(format.TextOverlay) > 0 {
+		unsupported = append(unsupported, "text_overlay")
+	}
+	if format.PackFiles != nil {
+		unsupported = append(unsupported, "pack_files")
+	}
+	for _, keyframe := range format.Keyframe {
+		if strings.Contains(keyframe, ",") {
+			unsupported = append(unsupported, "keyframe (values containing commas)")
+			break
+		}
+	}
+	if len(unsupported) > 0 {
+		return PresetFormat{}, fmt.Errorf("fields not supported in presets: %s", strings.Join(unsupported, ", "))
+	}
+	p := PresetFormat{
+		NoiseReduction:          format.NoiseReduction,
+		VideoCodec:              format.VideoCodec,
+		AudioCodec:              format.AudioCodec,
+		Bitrate:                 format.Bitrate,
+		AudioBitrate:            format.AudioBitrate,
+		AudioSampleRate:         format.AudioSampleRate,
+		AudioChannelsNumber:     format.AudioChannelsNumber,
+		AudioVolume:             format.AudioVolume,
+		Framerate:               format.Framerate,
+		FramerateUpperThreshold: format.FramerateUpperThreshold,
+		Size:                    format.Size,
+		FadeIn:                  format.FadeIn,
+		FadeOut:                 format.FadeOut,
+		CropLeft:                format.CropLeft,
+		CropTop:                 format.CropTop,
+		CropRight:               format.CropRight,
+		CropBottom:              format.CropBottom,
+		SetAspectRatio:          format.SetAspectRatio,
+		RcInitOccupancy:         format.RcInitOccupancy,
+		MinRate:                 format.MinRate,
+		MaxRate:                 format.MaxRate,
+		BufSize:                 format.BufSize,
+		Keyframe:                strings.Join(format.Keyframe, ","),
+		Start:                   format.Start,
+		Duration:                format.Duration,
+		ForceKeyframes:          format.ForceKeyframes,
+		Bframes:                 format.Bframes,
+		Gop:                     format.Gop,
+		Metadata:                format.Metadata,
+		Logo:                    format.Logo,
+		Profile:                 format.Profile,
+		Rotate:                  format.Rotate,
+		SetRotate:               format.SetRotate,
+		AudioSync:               format.AudioSync,
+		VideoSync:               format.VideoSync,
+		ForceInterlaced:         format.ForceInterlaced,
+		KeepAspectRatio:         format.KeepAspectRatio,
+		AddMeta:                 format.AddMeta,
+		Hint:                    format.Hint,
+		TwoPass:                 format.TwoPass,
+		Turbo:                   format.Turbo,
+		TwinTurbo:               format.TwinTurbo,
+		StripChapters:           format.StripChapters,
+	}
+	if len(format.Output) == 1 {
+		p.Output = format.Output[0]
+	}
+	if format.SegmentDuration > 0 {
+		p.SegmentDuration = strconv.FormatUint(uint64(format.SegmentDuration), 10)
+	}
+	if format.VideoCodecParameters != (VideoCodecParameters{}) {
+		p.VideoCodecParameters = toRawValue(format.VideoCodecParameters)
+	}
+	switch len(format.Stream) {
+	case 0:
+	case 1:
+		p.StreamRawMap = toRawValue(format.Stream[0])
+	default:
+		p.StreamRawMap = toRawValue(format.Stream)
+	}
+	return p, nil
+}
+
+// isEmptyRawValue returns whether the given raw value represents an unset
+// field. The API uses "no" in place of empty objects in some fields (e.g.
+// video_codec_parameters).
+func isEmptyRawValue(v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return v == "" || v == "no"
+	case map[string]interface{}:
+		return len(v) == 0
+	}
+	return false
+}
+
+// decodeRawValue decodes the given raw value into v, failing on fields that
+// don't exist in v, so no setting is silently dropped.
+func decodeRawValue(raw interface{}, v interface{}) error {
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
 // SavePresetResponse is the response returned in the SavePreset method.
 //
 // See http://goo.gl/q0xPuh for more details.
@@ -110,8 +337,8 @@ type SavePresetResponse struct {
 // See http://goo.gl/q0xPuh for more details.
 func (c *Client) SavePreset(name string, format Format) (*SavePresetResponse, error) {
 	var result map[string]struct {
-		Message     string `json:"message,omitempty"`
-		SavedPreset string `json:"SavedPreset,omitempty"`
+		Message     string `json:"message,omitempty" xml:"message,omitempty"`
+		SavedPreset string `json:"SavedPreset,omitempty" xml:"SavedPreset,omitempty"`
 	}
 	err := c.do(&request{Action: "SavePreset", Name: name, Format: []Format{format}}, &result)
 	if err != nil {
@@ -138,8 +365,8 @@ func (c *Client) GetPreset(name string) (*Preset, error) {
 //
 // See http://goo.gl/sugm5F for more details.
 type ListPresetsResponse struct {
-	UserPresets []Preset `json:"user"`
-	UIPresets   []Preset `json:"ui"`
+	UserPresets []Preset `json:"user" xml:"user>preset"`
+	UIPresets   []Preset `json:"ui" xml:"ui>preset"`
 }
 
 // ListPresets (GetPresetsList action in the Encoding.com API) returns a list
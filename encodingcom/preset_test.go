@@ -553,3 +553,222 @@ func TestDeletePresetError(t *testing.T) {
 		t.Errorf("unexpected non-nil resp: %#v", resp)
 	}
 }
+
+func TestPresetFormatFormat(t *testing.T) {
+	var presetJSON = []byte(`{
+	"output":"advanced_hls",
+	"video_codec":"libx264",
+	"audio_sample_rate":"44100",
+	"keyframe":"90,300",
+	"segment_duration":"6",
+	"crop_left":"10",
+	"two_pass":"yes",
+	"logo":{"logo_source":"http://example.com/logo.png","logo_x":"10"},
+	"video_codec_parameters":{"coder":"0","vprofile":"high"},
+	"stream":[
+		{"size":"1920x1080","bitrate":"5000k","video_codec_parameters":{"level":"40"}},
+		{"size":"640x360","bitrate":"800k","audio_only":"yes"}
+	]
+}`)
+	var preset PresetFormat
+	err := json.Unmarshal(presetJSON, &preset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	format, err := preset.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := Format{
+		Output:          []string{"advanced_hls"},
+		VideoCodec:      "libx264",
+		AudioSampleRate: 44100,
+		Keyframe:        []string{"90", "300"},
+		SegmentDuration: 6,
+		CropLeft:        10,
+		TwoPass:         YesNoBoolean(true),
+		Logo:            &Logo{LogoSourceURL: "http://example.com/logo.png", LogoX: 10},
+		VideoCodecParameters: VideoCodecParameters{
+			Coder:    "0",
+			Vprofile: "high",
+		},
+		Stream: []Stream{
+			{Size: "1920x1080", Bitrate: "5000k", VideoCodecParametersRaw: map[string]interface{}{"level": "40"}},
+			{Size: "640x360", Bitrate: "800k", AudioOnly: YesNoBoolean(true)},
+		},
+	}
+	if !reflect.DeepEqual(format, expected) {
+		t.Errorf("wrong format\nwant %#v\ngot  %#v", expected, format)
+	}
+
+	converted, err := NewPresetFormat(format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(converted, preset) {
+		t.Errorf("wrong preset format after round trip\nwant %#v\ngot  %#v", preset, converted)
+	}
+}
+
+func TestPresetFormatFormatSpecialValues(t *testing.T) {
+	var tests = []struct {
+		testCase string
+		preset   PresetFormat
+		expected Format
+	}{
+		{
+			"empty",
+			PresetFormat{},
+			Format{},
+		},
+		{
+			"video codec parameters set to no",
+			PresetFormat{VideoCodec: "libvpx", VideoCodecParameters: "no"},
+			Format{VideoCodec: "libvpx"},
+		},
+		{
+			"single stream object",
+			PresetFormat{StreamRawMap: map[string]interface{}{"size": "1280x720", "audio_volume": "100"}},
+			Format{Stream: []Stream{{Size: "1280x720", AudioVolume: 100}}},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.testCase, func(t *testing.T) {
+			format, err := test.preset.Format()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(format, test.expected) {
+				t.Errorf("wrong format\nwant %#v\ngot  %#v", test.expected, format)
+			}
+		})
+	}
+}
+
+func TestPresetFormatFormatErrors(t *testing.T) {
+	var tests = []struct {
+		testCase string
+		preset   PresetFormat
+		expected string
+	}{
+		{
+			"invalid segment duration",
+			PresetFormat{SegmentDuration: "6s"},
+			`invalid segment_duration "6s": strconv.ParseUint: parsing "6s": invalid syntax`,
+		},
+		{
+			"unknown video codec parameter",
+			PresetFormat{VideoCodecParameters: map[string]interface{}{"coder": "0", "refs": "3"}},
+			`invalid video_codec_parameters: json: unknown field "refs"`,
+		},
+		{
+			"unknown stream field",
+			PresetFormat{StreamRawMap: []interface{}{map[string]interface{}{"whatever": "1"}}},
+			`invalid stream: json: unknown field "whatever"`,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.testCase, func(t *testing.T) {
+			_, err := test.preset.Format()
+			if err == nil || err.Error() != test.expected {
+				t.Errorf("wrong error\nwant %q\ngot  %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestNewPresetFormat(t *testing.T) {
+	format := Format{
+		Output:               []string{"mp4"},
+		Size:                 "1280x720",
+		Keyframe:             []string{"300"},
+		SegmentDuration:      10,
+		VideoCodecParameters: VideoCodecParameters{Level: "31"},
+		Stream:               []Stream{{Size: "1280x720"}},
+	}
+	expected := PresetFormat{
+		Output:               "mp4",
+		Size:                 "1280x720",
+		Keyframe:             "300",
+		SegmentDuration:      "10",
+		VideoCodecParameters: map[string]interface{}{"level": "31"},
+		StreamRawMap:         map[string]interface{}{"size": "1280x720"},
+	}
+	preset, err := NewPresetFormat(format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(preset, expected) {
+		t.Errorf("wrong preset format\nwant %#v\ngot  %#v", expected, preset)
+	}
+	roundTrip, err := preset.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(roundTrip, format) {
+		t.Errorf("wrong format after round trip\nwant %#v\ngot  %#v", format, roundTrip)
+	}
+}
+
+func TestNewPresetFormatUnsupportedFields(t *testing.T) {
+	packFiles := YesNoBoolean(true)
+	format := Format{
+		Output:      []string{"mp4", "webm"},
+		Destination: []string{"s3://bucket/video.mp4"},
+		TextOverlay: []TextOverlay{{Text: []string{"hello"}}},
+		PackFiles:   &packFiles,
+		Keyframe:    []string{"1,2"},
+	}
+	expected := "fields not supported in presets: output (multiple values), destination, text_overlay, pack_files, keyframe (values containing commas)"
+	_, err := NewPresetFormat(format)
+	if err == nil || err.Error() != expected {
+		t.Errorf("wrong error\nwant %q\ngot  %v", expected, err)
+	}
+}
+
+func TestGetPresetAddMedia(t *testing.T) {
+	server, requests := startServer(`
+{
+	"response": {
+		"name":"hls",
+		"type":"user",
+		"output":"advanced_hls",
+		"format":{
+			"output":"advanced_hls",
+			"segment_duration":"6",
+			"keyframe":"90",
+			"video_codec_parameters":"no",
+			"stream":{"size":"1280x720","bitrate":"2000k"}
+		}
+	}
+}`)
+	defer server.Close()
+
+	client := Client{Endpoint: server.URL, UserID: "myuser", UserKey: "123"}
+	preset, err := client.GetPreset("hls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	<-requests
+	format, err := preset.Format.Format()
+	if err != nil {
+		t.Fatal(err)
+	}
+	format.Destination = []string{"s3://bucket/output/"}
+	client.AddMedia([]string{"http://example.com/video.mov"}, []Format{format}, "us-east-1")
+	req := <-requests
+	expectedFormat := map[string]interface{}{
+		"output":                 []interface{}{"advanced_hls"},
+		"segment_duration":       float64(6),
+		"keyframe":               []interface{}{"90"},
+		"destination":            []interface{}{"s3://bucket/output/"},
+		"video_codec_parameters": map[string]interface{}{},
+		"stream":                 []interface{}{map[string]interface{}{"size": "1280x720", "bitrate": "2000k"}},
+	}
+	formats := req.query["format"].([]interface{})
+	if len(formats) != 1 || !reflect.DeepEqual(formats[0], expectedFormat) {
+		t.Errorf("wrong format sent to encoding.com\nwant %#v\ngot  %#v", expectedFormat, formats)
+	}
+}
@@ -0,0 +1,188 @@
+package encodingcom
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// MediaStatus is the status of a media in the Encoding.com API.
+//
+// When unmarshaled from JSON or XML, known statuses are normalized regardless
+// of their case, so they can be safely compared with the constants declared
+// in this package.
+type MediaStatus string
+
+const (
+	// MediaStatusNew is the status of media that was just added.
+	MediaStatusNew = MediaStatus("New")
+
+	// MediaStatusDownloading is the status of media whose source is being
+	// downloaded.
+	MediaStatusDownloading = MediaStatus("Downloading")
+
+	// MediaStatusReadyToProcess is the status of media that was downloaded
+	// and is ready to be processed.
+	MediaStatusReadyToProcess = MediaStatus("Ready to process")
+
+	// MediaStatusWaitingForEncoder is the status of media waiting for an
+	// available encoder.
+	MediaStatusWaitingForEncoder = MediaStatus("Waiting for encoder")
+
+	// MediaStatusProcessing is the status of media being encoded.
+	MediaStatusProcessing = MediaStatus("Processing")
+
+	// MediaStatusSaving is the status of media whose outputs are being
+	// saved to their destinations.
+	MediaStatusSaving = MediaStatus("Saving")
+
+	// MediaStatusFinished is the status of media that was successfully
+	// encoded.
+	MediaStatusFinished = MediaStatus("Finished")
+
+	// MediaStatusError is the status of media that failed.
+	MediaStatusError = MediaStatus("Error")
+
+	// MediaStatusDeleted is the status of media that was cancelled.
+	MediaStatusDeleted = MediaStatus("Deleted")
+)
+
+var knownMediaStatuses = []MediaStatus{
+	MediaStatusNew,
+	MediaStatusDownloading,
+	MediaStatusReadyToProcess,
+	MediaStatusWaitingForEncoder,
+	MediaStatusProcessing,
+	MediaStatusSaving,
+	MediaStatusFinished,
+	MediaStatusError,
+	MediaStatusDeleted,
+}
+
+// IsTerminal returns whether the status represents a media that won't make
+// any progress anymore (finished, errored or deleted).
+func (s MediaStatus) IsTerminal() bool {
+	switch s {
+	case MediaStatusFinished, MediaStatusError, MediaStatusDeleted:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON normalizes the case of known statuses. Values that aren't
+// strings (Encoding.com sometimes returns an empty list) are unmarshaled as
+// an empty status.
+func (s *MediaStatus) UnmarshalJSON(data []byte) error {
+	*s = parseMediaStatus(unmarshalStatus(data))
+	return nil
+}
+
+// UnmarshalText normalizes the case of known statuses in XML responses.
+func (s *MediaStatus) UnmarshalText(data []byte) error {
+	*s = parseMediaStatus(strings.TrimSpace(string(data)))
+	return nil
+}
+
+func parseMediaStatus(value string) MediaStatus {
+	for _, status := range knownMediaStatuses {
+		if strings.EqualFold(value, string(status)) {
+			return status
+		}
+	}
+	return MediaStatus(value)
+}
+
+// TaskStatus is the status of a task (an output format, or the destination
+// of an output format) of a media in the Encoding.com API.
+//
+// When unmarshaled from JSON or XML, known statuses are normalized regardless
+// of their case, so they can be safely compared with the constants declared
+// in this package.
+type TaskStatus string
+
+const (
+	// TaskStatusNew is the status of tasks that were just created.
+	TaskStatusNew = TaskStatus("New")
+
+	// TaskStatusDownloading is the status of tasks whose source is being
+	// downloaded.
+	TaskStatusDownloading = TaskStatus("Downloading")
+
+	// TaskStatusReadyToProcess is the status of tasks ready to be
+	// processed.
+	TaskStatusReadyToProcess = TaskStatus("Ready to process")
+
+	// TaskStatusWaitingForEncoder is the status of tasks waiting for an
+	// available encoder.
+	TaskStatusWaitingForEncoder = TaskStatus("Waiting for encoder")
+
+	// TaskStatusProcessing is the status of tasks being encoded.
+	TaskStatusProcessing = TaskStatus("Processing")
+
+	// TaskStatusSaving is the status of tasks whose output is being saved.
+	TaskStatusSaving = TaskStatus("Saving")
+
+	// TaskStatusSaved is the status of destinations where the output was
+	// saved.
+	TaskStatusSaved = TaskStatus("Saved")
+
+	// TaskStatusFinished is the status of tasks that were successfully
+	// completed.
+	TaskStatusFinished = TaskStatus("Finished")
+
+	// TaskStatusError is the status of tasks that failed.
+	TaskStatusError = TaskStatus("Error")
+
+	// TaskStatusDeleted is the status of tasks that were cancelled.
+	TaskStatusDeleted = TaskStatus("Deleted")
+)
+
+var knownTaskStatuses = []TaskStatus{
+	TaskStatusNew,
+	TaskStatusDownloading,
+	TaskStatusReadyToProcess,
+	TaskStatusWaitingForEncoder,
+	TaskStatusProcessing,
+	TaskStatusSaving,
+	TaskStatusSaved,
+	TaskStatusFinished,
+	TaskStatusError,
+	TaskStatusDeleted,
+}
+
+// IsTerminal returns whether the status represents a task that won't make
+// any progress anymore (saved, finished, errored or deleted).
+func (s TaskStatus) IsTerminal() bool {
+	switch s {
+	case TaskStatusSaved, TaskStatusFinished, TaskStatusError, TaskStatusDeleted:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON normalizes the case of known statuses. Values that aren't
+// strings are unmarshaled as an empty status.
+func (s *TaskStatus) UnmarshalJSON(data []byte) error {
+	*s = parseTaskStatus(unmarshalStatus(data))
+	return nil
+}
+
+// UnmarshalText normalizes the case of known statuses in XML responses.
+func (s *TaskStatus) UnmarshalText(data []byte) error {
+	*s = parseTaskStatus(strings.TrimSpace(string(data)))
+	return nil
+}
+
+func parseTaskStatus(value string) TaskStatus {
+	for _, status := range knownTaskStatuses {
+		if strings.EqualFold(value, string(status)) {
+			return status
+		}
+	}
+	return TaskStatus(value)
+}
+
+func unmarshalStatus(data []byte) string {
+	var value string
+	json.Unmarshal(data, &value)
+	return strings.TrimSpace(value)
+}
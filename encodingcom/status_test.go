@@ -0,0 +1,146 @@
+package encodingcom
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestMediaStatusUnmarshalJSON(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected MediaStatus
+	}{
+		{`"Finished"`, MediaStatusFinished},
+		{`"finished"`, MediaStatusFinished},
+		{`"READY TO PROCESS"`, MediaStatusReadyToProcess},
+		{`"Waiting for Encoder"`, MediaStatusWaitingForEncoder},
+		{`" Processing "`, MediaStatusProcessing},
+		{`"Something new"`, MediaStatus("Something new")},
+		{`[]`, MediaStatus("")},
+		{`null`, MediaStatus("")},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.input, func(t *testing.T) {
+			var status MediaStatus
+			err := json.Unmarshal([]byte(test.input), &status)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status != test.expected {
+				t.Errorf("wrong status\nwant %q\ngot  %q", test.expected, status)
+			}
+		})
+	}
+}
+
+func TestTaskStatusUnmarshalJSON(t *testing.T) {
+	var tests = []struct {
+		input    string
+		expected TaskStatus
+	}{
+		{`"Saved"`, TaskStatusSaved},
+		{`"saved"`, TaskStatusSaved},
+		{`"ERROR"`, TaskStatusError},
+		{`"Something new"`, TaskStatus("Something new")},
+		{`[]`, TaskStatus("")},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.input, func(t *testing.T) {
+			var status TaskStatus
+			err := json.Unmarshal([]byte(test.input), &status)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if status != test.expected {
+				t.Errorf("wrong status\nwant %q\ngot  %q", test.expected, status)
+			}
+		})
+	}
+}
+
+func TestMediaStatusIsTerminal(t *testing.T) {
+	var tests = []struct {
+		status   MediaStatus
+		terminal bool
+	}{
+		{MediaStatusNew, false},
+		{MediaStatusDownloading, false},
+		{MediaStatusReadyToProcess, false},
+		{MediaStatusWaitingForEncoder, false},
+		{MediaStatusProcessing, false},
+		{MediaStatusSaving, false},
+		{MediaStatusFinished, true},
+		{MediaStatusError, true},
+		{MediaStatusDeleted, true},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(string(test.status), func(t *testing.T) {
+			if got := test.status.IsTerminal(); got != test.terminal {
+				t.Errorf("wrong IsTerminal\nwant %v\ngot  %v", test.terminal, got)
+			}
+		})
+	}
+}
+
+func TestTaskStatusIsTerminal(t *testing.T) {
+	var tests = []struct {
+		status   TaskStatus
+		terminal bool
+	}{
+		{TaskStatusNew, false},
+		{TaskStatusProcessing, false},
+		{TaskStatusSaving, false},
+		{TaskStatusSaved, true},
+		{TaskStatusFinished, true},
+		{TaskStatusError, true},
+		{TaskStatusDeleted, true},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(string(test.status), func(t *testing.T) {
+			if got := test.status.IsTerminal(); got != test.terminal {
+				t.Errorf("wrong IsTerminal\nwant %v\ngot  %v", test.terminal, got)
+			}
+		})
+	}
+}
+
+func TestGetStatusNormalizesStatuses(t *testing.T) {
+	server, _ := startServer(`
+{
+	"response": {
+		"job": {
+			"id": "abc123",
+			"status": "processing",
+			"prevstatus": "waiting for encoder",
+			"format": {
+				"id": "f123",
+				"status": "PROCESSING",
+				"destination": "s3://mynicebucket",
+				"destination_status": "saving"
+			}
+		}
+	}
+}`)
+	defer server.Close()
+	client := Client{Endpoint: server.URL, UserID: "myuser", UserKey: "123"}
+	status, err := client.GetStatus([]string{"abc123"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status[0].MediaStatus != MediaStatusProcessing {
+		t.Errorf("wrong media status\nwant %q\ngot  %q", MediaStatusProcessing, status[0].MediaStatus)
+	}
+	if status[0].PreviousMediaStatus != MediaStatusWaitingForEncoder {
+		t.Errorf("wrong previous media status\nwant %q\ngot  %q", MediaStatusWaitingForEncoder, status[0].PreviousMediaStatus)
+	}
+	if status[0].Formats[0].Status != TaskStatusProcessing {
+		t.Errorf("wrong format status\nwant %q\ngot  %q", TaskStatusProcessing, status[0].Formats[0].Status)
+	}
+	if status[0].Formats[0].Destinations[0].Status != TaskStatusSaving {
+		t.Errorf("wrong destination status\nwant %q\ngot  %q", TaskStatusSaving, status[0].Formats[0].Destinations[0].Status)
+	}
+}
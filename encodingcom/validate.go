@@ -0,0 +1,227 @@
+package encodingcom
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var sizeRegexp = regexp.MustCompile(`^\d+x\d+$`)
+
+// outputVideoCodecs lists the video codecs supported by outputs that only
+// accept a few codecs. Outputs that aren't listed aren't checked. All of them
+// accept "copy", which passes the source stream through without re-encoding.
+var outputVideoCodecs = map[string][]string{
+	"mp4":  {"libx264", "libx265", "mpeg4", "copy"},
+	"m4v":  {"libx264", "mpeg4", "copy"},
+	"webm": {"libvpx", "libvpx-vp9", "copy"},
+	"ogg":  {"libtheora", "copy"},
+}
+
+// outputAudioCodecs lists the audio codecs supported by outputs that only
+// accept a few codecs. Outputs that aren't listed aren't checked. As with
+// video, "copy" is always accepted.
+var outputAudioCodecs = map[string][]string{
+	"webm": {"libvorbis", "libopus", "copy"},
+	"ogg":  {"libvorbis", "copy"},
+}
+
+// hlsOutputs are the outputs that produce segmented HLS streams, requiring a
+// segment duration.
+var hlsOutputs = map[string]bool{
+	"advanced_hls":  true,
+	"iphone_stream": true,
+	"ipad_stream":   true,
+}
+
+// ValidationError represents an invalid value in a field of a Format. Field
+// is the path of the field, using the names in the JSON representation of
+// the format (e.g. "stream[1].size" or "logo.logo_source").
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// Error returns the path of the field followed by the message.
+func (err *ValidationError) Error() string {
+	return err.Field + ": " + err.Message
+}
+
+// ValidationErrors is the error returned by the Validate methods, listing
+// all the invalid fields found.
+type ValidationErrors []*ValidationError
+
+// Error returns a message including all the errors.
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return "invalid format: " + strings.Join(messages, "; ")
+}
+
+func (errs *ValidationErrors) add(field, format string, args ...interface{}) {
+	*errs = append(*errs, &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// merge appends the errors returned by a nested Validate method, prefixing
+// their fields with path.
+func (errs *ValidationErrors) merge(path string, err error) {
+	nested, ok := err.(ValidationErrors)
+	if !ok {
+		return
+	}
+	for _, e := range nested {
+		*errs = append(*errs, &ValidationError{Field: path + e.Field, Message: e.Message})
+	}
+}
+
+func (errs ValidationErrors) err() error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Validate checks the format for common mistakes that would only be reported
+// by the API after the media fails, returning an error of type
+// ValidationErrors listing all the invalid fields, or nil when no problem is
+// found. It checks:
+//
+//   - that either output or output_preset is set;
+//   - the format of sizes (<width>x<height>);
+//   - that crop values aren't negative;
+//   - the video and audio codecs of outputs that support only a few codecs
+//     (e.g. webm);
+//   - that HLS outputs define a segment duration;
+//   - the logo, overlays, text overlays and streams, using their Validate
+//     methods.
+//
+// Validate doesn't check whether the values are accepted by the API, so a
+// valid format may still be rejected.
+func (f Format) Validate() error {
+	var errs ValidationErrors
+	if len(f.Output) == 0 && f.OutputPreset == "" {
+		errs.add("output", "either output or output_preset is required")
+	}
+	validateSize(&errs, "size", f.Size)
+	for _, crop := range []struct {
+		field string
+		value int
+	}{
+		{"crop_left", f.CropLeft},
+		{"crop_top", f.CropTop},
+		{"crop_right", f.CropRight},
+		{"crop_bottom", f.CropBottom},
+	} {
+		if crop.value < 0 {
+			errs.add(crop.field, "must not be negative (%d)", crop.value)
+		}
+	}
+	for _, output := range f.Output {
+		validateCodec(&errs, "video_codec", output, f.VideoCodec, outputVideoCodecs)
+		validateCodec(&errs, "audio_codec", output, f.AudioCodec, outputAudioCodecs)
+		if hlsOutputs[output] && f.SegmentDuration == 0 {
+			errs.add("segment_duration", "is required for %s outputs", output)
+		}
+	}
+	if f.Logo != nil {
+		errs.merge("logo.", f.Logo.Validate())
+	}
+	for i, overlay := range f.Overlay {
+		errs.merge(fmt.Sprintf("overlay[%d].", i), overlay.Validate())
+	}
+	for i, overlay := range f.TextOverlay {
+		errs.merge(fmt.Sprintf("text_overlay[%d].", i), overlay.Validate())
+	}
+	for i, stream := range f.Stream {
+		errs.merge(fmt.Sprintf("stream[%d].", i), stream.Validate())
+	}
+	return errs.err()
+}
+
+// Validate checks the settings of the stream, returning an error of type
+// ValidationErrors, or nil when no problem is found. It checks the format of
+// sizes and that the stream isn't both audio only and video only.
+func (s Stream) Validate() error {
+	var errs ValidationErrors
+	validateSize(&errs, "size", s.Size)
+	validateSize(&errs, "still_image_size", s.StillImageSize)
+	if s.AudioOnly && s.VideoOnly {
+		errs.add("audio_only", "can't be combined with video_only")
+	}
+	return errs.err()
+}
+
+// Validate checks the settings of the logo, returning an error of type
+// ValidationErrors, or nil when no problem is found. It checks that the
+// source of the logo is set and that its position isn't negative.
+func (l Logo) Validate() error {
+	var errs ValidationErrors
+	if l.LogoSourceURL == "" {
+		errs.add("logo_source", "is required")
+	}
+	if l.LogoX < 0 {
+		errs.add("logo_x", "must not be negative (%d)", l.LogoX)
+	}
+	if l.LogoY < 0 {
+		errs.add("logo_y", "must not be negative (%d)", l.LogoY)
+	}
+	return errs.err()
+}
+
+// Validate checks the settings of the overlay, returning an error of type
+// ValidationErrors, or nil when no problem is found. It checks that the
+// source of the overlay is set, the format of the size and that start and
+// duration aren't negative.
+func (o Overlay) Validate() error {
+	var errs ValidationErrors
+	if o.OverlaySource == "" {
+		errs.add("overlay_source", "is required")
+	}
+	validateSize(&errs, "size", o.Size)
+	validateTiming(&errs, o.OverlayStart, o.OverlayDuration)
+	return errs.err()
+}
+
+// Validate checks the settings of the text overlay, returning an error of
+// type ValidationErrors, or nil when no problem is found. It checks that
+// some text is set, the format of the size and that start and duration
+// aren't negative.
+func (o TextOverlay) Validate() error {
+	var errs ValidationErrors
+	if len(o.Text) == 0 {
+		errs.add("text", "is required")
+	}
+	validateSize(&errs, "size", o.Size)
+	validateTiming(&errs, o.OverlayStart, o.OverlayDuration)
+	return errs.err()
+}
+
+func validateSize(errs *ValidationErrors, field, size string) {
+	if size != "" && !sizeRegexp.MatchString(size) {
+		errs.add(field, "invalid size %q, expected <width>x<height>", size)
+	}
+}
+
+func validateTiming(errs *ValidationErrors, start, duration float64) {
+	if start < 0 {
+		errs.add("overlay_start", "must not be negative (%g)", start)
+	}
+	if duration < 0 {
+		errs.add("overlay_duration", "must not be negative (%g)", duration)
+	}
+}
+
+func validateCodec(errs *ValidationErrors, field, output, codec string, supported map[string][]string) {
+	codecs, ok := supported[output]
+	if !ok || codec == "" {
+		return
+	}
+	for _, c := range codecs {
+		if c == codec {
+			return
+		}
+	}
+	errs.add(field, "%s is not supported in %s outputs (supported: %s)", codec, output, strings.Join(codecs, ", "))
+}
@@ -0,0 +1,118 @@
+package encodingcom
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFormatValidate(t *testing.T) {
+	var tests = []struct {
+		testCase string
+		format   Format
+		expected error
+	}{
+		{
+			"valid format",
+			Format{
+				Output:     []string{"mp4"},
+				VideoCodec: "libx264",
+				AudioCodec: "dolby_aac",
+				Size:       "1920x1080",
+				Logo:       &Logo{LogoSourceURL: "http://example.com/logo.png", LogoX: 10},
+				Overlay:    []Overlay{{OverlaySource: "http://example.com/overlay.mov", Size: "320x240", OverlayStart: 5}},
+			},
+			nil,
+		},
+		{
+			"valid HLS format",
+			Format{
+				Output:          []string{"advanced_hls"},
+				SegmentDuration: 6,
+				Stream:          []Stream{{Size: "1280x720", Bitrate: "2500k"}, {Size: "0x360", AudioOnly: true}},
+			},
+			nil,
+		},
+		{
+			"valid pass-through format",
+			Format{
+				Output:     []string{"webm", "mp4"},
+				VideoCodec: "copy",
+				AudioCodec: "copy",
+			},
+			nil,
+		},
+		{
+			"output preset",
+			Format{OutputPreset: "my-preset"},
+			nil,
+		},
+		{
+			"missing output",
+			Format{VideoCodec: "libx264"},
+			ValidationErrors{
+				{Field: "output", Message: "either output or output_preset is required"},
+			},
+		},
+		{
+			"invalid size and crops",
+			Format{Output: []string{"mp4"}, Size: "1080p", CropLeft: -1, CropBottom: -20},
+			ValidationErrors{
+				{Field: "size", Message: `invalid size "1080p", expected <width>x<height>`},
+				{Field: "crop_left", Message: "must not be negative (-1)"},
+				{Field: "crop_bottom", Message: "must not be negative (-20)"},
+			},
+		},
+		{
+			"unsupported codecs",
+			Format{Output: []string{"webm", "mp4"}, VideoCodec: "libx264", AudioCodec: "libfaac"},
+			ValidationErrors{
+				{Field: "video_codec", Message: "libx264 is not supported in webm outputs (supported: libvpx, libvpx-vp9, copy)"},
+				{Field: "audio_codec", Message: "libfaac is not supported in webm outputs (supported: libvorbis, libopus, copy)"},
+			},
+		},
+		{
+			"HLS without segment duration",
+			Format{Output: []string{"advanced_hls"}, Stream: []Stream{{Size: "1280x720"}}},
+			ValidationErrors{
+				{Field: "segment_duration", Message: "is required for advanced_hls outputs"},
+			},
+		},
+		{
+			"nested errors",
+			Format{
+				Output:      []string{"mp4"},
+				Logo:        &Logo{LogoY: -5},
+				Overlay:     []Overlay{{OverlaySource: "http://example.com/overlay.mov"}, {OverlayDuration: -2.5}},
+				TextOverlay: []TextOverlay{{Size: "big"}},
+				Stream:      []Stream{{}, {StillImageSize: "640 x 360", AudioOnly: true, VideoOnly: true}},
+			},
+			ValidationErrors{
+				{Field: "logo.logo_source", Message: "is required"},
+				{Field: "logo.logo_y", Message: "must not be negative (-5)"},
+				{Field: "overlay[1].overlay_source", Message: "is required"},
+				{Field: "overlay[1].overlay_duration", Message: "must not be negative (-2.5)"},
+				{Field: "text_overlay[0].text", Message: "is required"},
+				{Field: "text_overlay[0].size", Message: `invalid size "big", expected <width>x<height>`},
+				{Field: "stream[1].still_image_size", Message: `invalid size "640 x 360", expected <width>x<height>`},
+				{Field: "stream[1].audio_only", Message: "can't be combined with video_only"},
+			},
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.testCase, func(t *testing.T) {
+			err := test.format.Validate()
+			if !reflect.DeepEqual(err, test.expected) {
+				t.Errorf("wrong error\nwant %#v\ngot  %#v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestValidationErrorsMessage(t *testing.T) {
+	err := Format{Output: []string{"mp4"}, Size: "big", CropTop: -1}.Validate()
+	expected := `invalid format: size: invalid size "big", expected <width>x<height>; crop_top: must not be negative (-1)`
+	if err == nil || err.Error() != expected {
+		t.Errorf("wrong error message\nwant %q\ngot  %v", expected, err)
+	}
+}
@@ -0,0 +1,113 @@
+package encodingcom
+
+import (
+	"encoding/json"
+	"encoding/xml"
+)
+
+// The types below have fields that can't be represented as XML directly
+// (either because they hold arbitrary JSON values or because the zero value
+// must be omitted), so they implement xml.Marshaler and xml.Unmarshaler using
+// aliases that don't carry the methods.
+
+type videoCodecParametersXML VideoCodecParameters
+
+// MarshalXML omits VideoCodecParameters from the XML when no parameter is
+// set.
+func (p VideoCodecParameters) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if p == (VideoCodecParameters{}) {
+		return nil
+	}
+	return e.EncodeElement(videoCodecParametersXML(p), start)
+}
+
+// UnmarshalXML is the method that ensures that VideoCodecParameters can be
+// unmarshaled from XML.
+func (p *VideoCodecParameters) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	return d.DecodeElement((*videoCodecParametersXML)(p), &start)
+}
+
+type streamAlias Stream
+
+type streamXML struct {
+	streamAlias
+	VideoCodecParameters *VideoCodecParameters `xml:"video_codec_parameters,omitempty"`
+}
+
+// MarshalXML encodes the stream as XML, including the raw video codec
+// parameters.
+func (s Stream) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	v := streamXML{streamAlias: streamAlias(s)}
+	if s.VideoCodecParametersRaw != nil {
+		params := s.VideoCodecParameters()
+		v.VideoCodecParameters = &params
+	}
+	return e.EncodeElement(v, start)
+}
+
+// UnmarshalXML decodes the stream from XML, storing video codec parameters in
+// VideoCodecParametersRaw, matching the behavior of JSON decoding.
+func (s *Stream) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v streamXML
+	err := d.DecodeElement(&v, &start)
+	if err != nil {
+		return err
+	}
+	*s = Stream(v.streamAlias)
+	if v.VideoCodecParameters != nil {
+		s.VideoCodecParametersRaw = toRawValue(v.VideoCodecParameters)
+	}
+	return nil
+}
+
+type presetFormatAlias PresetFormat
+
+type presetFormatXML struct {
+	presetFormatAlias
+	VideoCodecParameters *VideoCodecParameters `xml:"video_codec_parameters,omitempty"`
+	Stream               []Stream              `xml:"stream,omitempty"`
+}
+
+// MarshalXML encodes the preset format as XML, including the raw video codec
+// parameters and streams.
+func (p PresetFormat) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	v := presetFormatXML{presetFormatAlias: presetFormatAlias(p)}
+	if p.VideoCodecParameters != nil {
+		var params VideoCodecParameters
+		data, _ := json.Marshal(p.VideoCodecParameters)
+		json.Unmarshal(data, &params)
+		v.VideoCodecParameters = &params
+	}
+	if p.StreamRawMap != nil {
+		v.Stream = p.Stream()
+	}
+	return e.EncodeElement(v, start)
+}
+
+// UnmarshalXML decodes the preset format from XML, storing video codec
+// parameters and streams in the raw fields, matching the behavior of JSON
+// decoding.
+func (p *PresetFormat) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var v presetFormatXML
+	err := d.DecodeElement(&v, &start)
+	if err != nil {
+		return err
+	}
+	*p = PresetFormat(v.presetFormatAlias)
+	if v.VideoCodecParameters != nil {
+		p.VideoCodecParameters = toRawValue(v.VideoCodecParameters)
+	}
+	if len(v.Stream) > 0 {
+		p.StreamRawMap = toRawValue(v.Stream)
+	}
+	return nil
+}
+
+// toRawValue converts the given value to the generic representation used in
+// fields that hold raw JSON values (maps and slices of interface{}).
+func toRawValue(v interface{}) interface{} {
+	var raw interface{}
+	data, _ := json.Marshal(v)
+	json.Unmarshal(data, &raw)
+	return raw
+}
@@ -0,0 +1,319 @@
+package encodingcom
+
+import (
+	"encoding/xml"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAddMediaXML(t *testing.T) {
+	server, requests := startXMLServer(`<?xml version="1.0"?>
+<response>
+	<message>Added</message>
+	<MediaID>1234567</MediaID>
+</response>`)
+	defer server.Close()
+
+	client := Client{Endpoint: server.URL, UserID: "myuser", UserKey: "123", WireFormat: XMLWireFormat}
+	format := Format{
+		Output:       []string{"mp4"},
+		VideoCodec:   "x264",
+		AudioCodec:   "aac",
+		Bitrate:      "900k",
+		AudioBitrate: "64k",
+		TwoPass:      true,
+		Stream: []Stream{
+			{
+				Bitrate:                 "600k",
+				VideoCodecParametersRaw: map[string]interface{}{"coder": "0"},
+			},
+		},
+	}
+	addMediaResponse, err := client.AddMedia([]string{"http://another.non.existent/video.mov"},
+		[]Format{format}, "us-east-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedResp := &AddMediaResponse{
+		Message: "Added",
+		MediaID: "1234567",
+	}
+	if !reflect.DeepEqual(addMediaResponse, expectedResp) {
+		t.Errorf("wrong response\nwant %#v\ngot  %#v", expectedResp, addMediaResponse)
+	}
+	req := <-requests
+	if req.query.UserID != "myuser" || req.query.UserKey != "123" {
+		t.Errorf("wrong credentials in the request: %#v", req.query)
+	}
+	if expectedAction := "AddMedia"; req.query.Action != expectedAction {
+		t.Errorf("wrong action\nwant %q\ngot  %q", expectedAction, req.query.Action)
+	}
+	expectedSource := []string{"http://another.non.existent/video.mov"}
+	if !reflect.DeepEqual(req.query.Source, expectedSource) {
+		t.Errorf("wrong source\nwant %#v\ngot  %#v", expectedSource, req.query.Source)
+	}
+	expectedFormat := format
+	expectedFormat.Stream = []Stream{
+		{
+			Bitrate:                 "600k",
+			VideoCodecParametersRaw: map[string]interface{}{"coder": "0"},
+		},
+	}
+	if !reflect.DeepEqual(req.query.Format, []Format{expectedFormat}) {
+		t.Errorf("wrong format\nwant %#v\ngot  %#v", []Format{expectedFormat}, req.query.Format)
+	}
+}
+
+func TestXMLRequestBody(t *testing.T) {
+	data, err := xml.Marshal(&request{
+		UserID:   "myuser",
+		UserKey:  "123",
+		Action:   "GetStatus",
+		MediaID:  "abc123",
+		Extended: true,
+		Format:   []Format{{Output: []string{"mp4"}, TwoPass: true}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "<query><userid>myuser</userid><userkey>123</userkey><action>GetStatus</action>" +
+		"<mediaid>abc123</mediaid><extended>yes</extended>" +
+		"<format><output>mp4</output><two_pass>yes</two_pass></format></query>"
+	if string(data) != expected {
+		t.Errorf("wrong XML request\nwant %s\ngot  %s", expected, data)
+	}
+}
+
+func TestDoXMLError(t *testing.T) {
+	server, _ := startXMLServer(`<?xml version="1.0"?>
+<response>
+	<message>Deleted</message>
+	<errors>
+		<error>something went wrong</error>
+	</errors>
+</response>`)
+	defer server.Close()
+
+	client := Client{Endpoint: server.URL, UserID: "myuser", UserKey: "123", WireFormat: XMLWireFormat}
+	resp, err := client.CancelMedia("123")
+	if resp != nil {
+		t.Errorf("unexpected non-nil response: %#v", resp)
+	}
+	expectedErr := &APIError{Message: "Deleted", Errors: []string{"something went wrong"}}
+	if !reflect.DeepEqual(err, expectedErr) {
+		t.Errorf("wrong error\nwant %#v\ngot  %#v", expectedErr, err)
+	}
+}
+
+func TestGetStatusXML(t *testing.T) {
+	server, requests := startXMLServer(`<?xml version="1.0"?>
+<response>
+	<job>
+		<id>abc123</id>
+		<userid>myuser</userid>
+		<sourcefile>http://some.video/file.mp4</sourcefile>
+		<status>Finished</status>
+		<created>2015-12-31 20:45:30</created>
+		<started>2015-12-31 20:45:34</started>
+		<finished>2015-12-31 21:00:03</finished>
+		<downloaded>0000-00-00 00:00:00</downloaded>
+		<time_left>0</time_left>
+		<progress>100.0</progress>
+		<format>
+			<id>f123</id>
+			<status>Finished</status>
+			<size>1920x1080</size>
+			<destination>s3://mynicebucket/file.mp4</destination>
+			<destination>s3://myothernicebucket/file.mp4</destination>
+			<destination_status>Saved</destination_status>
+			<destination_status>Error</destination_status>
+		</format>
+		<format>
+			<id>f124</id>
+			<status>Processing</status>
+		</format>
+	</job>
+	<job>
+		<id>abc124</id>
+		<status>Processing</status>
+		<time_left>12</time_left>
+	</job>
+</response>`)
+	defer server.Close()
+
+	client := Client{Endpoint: server.URL, UserID: "myuser", UserKey: "123", WireFormat: XMLWireFormat}
+	status, err := client.GetStatus([]string{"abc123", "abc124"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := <-requests
+	if req.query.MediaID != "abc123,abc124" || req.query.Extended != "yes" {
+		t.Errorf("wrong request: %#v", req.query)
+	}
+	createDate := time.Date(2015, 12, 31, 20, 45, 30, 0, DefaultLocation)
+	startDate := time.Date(2015, 12, 31, 20, 45, 34, 0, DefaultLocation)
+	finishDate := time.Date(2015, 12, 31, 21, 0, 3, 0, DefaultLocation)
+	expected := []StatusResponse{
+		{
+			MediaID:            "abc123",
+			UserID:             "myuser",
+			SourceFile:         "http://some.video/file.mp4",
+			MediaStatus:        MediaStatusFinished,
+			CreateDate:         createDate,
+			StartDate:          startDate,
+			FinishDate:         finishDate,
+			TimeLeft:           0,
+			Progress:           100,
+			TimeLeftCurrentJob: UnknownTimeLeft,
+			Formats: []FormatStatus{
+				{
+					ID:     "f123",
+					Status: TaskStatusFinished,
+					Size:   "1920x1080",
+					Destinations: []DestinationStatus{
+						{Name: "s3://mynicebucket/file.mp4", Status: TaskStatusSaved},
+						{Name: "s3://myothernicebucket/file.mp4", Status: TaskStatusError},
+					},
+				},
+				{ID: "f124", Status: TaskStatusProcessing},
+			},
+		},
+		{
+			MediaID:            "abc124",
+			MediaStatus:        MediaStatusProcessing,
+			TimeLeft:           12 * time.Second,
+			TimeLeftCurrentJob: UnknownTimeLeft,
+			Formats:            []FormatStatus{},
+		},
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("wrong status\nwant %#v\ngot  %#v", expected, status)
+	}
+}
+
+func TestGetStatusXMLNotExtended(t *testing.T) {
+	server, _ := startXMLServer(`<?xml version="1.0"?>
+<response>
+	<id>abc123</id>
+	<status>Saving</status>
+	<progress>99.5</progress>
+</response>`)
+	defer server.Close()
+
+	client := Client{Endpoint: server.URL, UserID: "myuser", UserKey: "123", WireFormat: XMLWireFormat}
+	status, err := client.GetStatus([]string{"abc123"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(status) != 1 {
+		t.Fatalf("wrong number of items\nwant 1\ngot  %d", len(status))
+	}
+	if status[0].MediaID != "abc123" || status[0].MediaStatus != MediaStatusSaving || status[0].Progress != 99.5 {
+		t.Errorf("wrong status: %#v", status[0])
+	}
+}
+
+func TestGetPresetXML(t *testing.T) {
+	server, requests := startXMLServer(`<?xml version="1.0"?>
+<response>
+	<name>webm_1080p</name>
+	<type>user</type>
+	<output>webm</output>
+	<format>
+		<output>webm</output>
+		<video_codec>libvpx</video_codec>
+		<bitrate>2000k</bitrate>
+		<two_pass>yes</two_pass>
+		<video_codec_parameters>
+			<coder>0</coder>
+		</video_codec_parameters>
+		<stream>
+			<bitrate>600k</bitrate>
+		</stream>
+		<stream>
+			<bitrate>1200k</bitrate>
+		</stream>
+	</format>
+</response>`)
+	defer server.Close()
+
+	client := Client{Endpoint: server.URL, UserID: "myuser", UserKey: "123", WireFormat: XMLWireFormat}
+	preset, err := client.GetPreset("webm_1080p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := <-requests
+	if req.query.Action != "GetPreset" || req.query.Name != "webm_1080p" {
+		t.Errorf("wrong request: %#v", req.query)
+	}
+	expectedFormat := PresetFormat{
+		Output:               "webm",
+		VideoCodec:           "libvpx",
+		Bitrate:              "2000k",
+		TwoPass:              true,
+		VideoCodecParameters: map[string]interface{}{"coder": "0"},
+		StreamRawMap: []interface{}{
+			map[string]interface{}{"bitrate": "600k"},
+			map[string]interface{}{"bitrate": "1200k"},
+		},
+	}
+	expected := &Preset{Name: "webm_1080p", Type: UserPresets, Output: "webm", Format: expectedFormat}
+	if !reflect.DeepEqual(preset, expected) {
+		t.Errorf("wrong preset\nwant %#v\ngot  %#v", expected, preset)
+	}
+	expectedStreams := []Stream{{Bitrate: "600k"}, {Bitrate: "1200k"}}
+	if streams := preset.Format.Stream(); !reflect.DeepEqual(streams, expectedStreams) {
+		t.Errorf("wrong streams\nwant %#v\ngot  %#v", expectedStreams, streams)
+	}
+}
+
+func TestListPresetsXML(t *testing.T) {
+	server, _ := startXMLServer(`<?xml version="1.0"?>
+<response>
+	<user>
+		<preset><name>my-preset</name><type>user</type></preset>
+	</user>
+	<ui>
+		<preset><name>mp4_1080p</name><type>ui</type></preset>
+		<preset><name>webm_1080p</name><type>ui</type></preset>
+	</ui>
+</response>`)
+	defer server.Close()
+
+	client := Client{Endpoint: server.URL, UserID: "myuser", UserKey: "123", WireFormat: XMLWireFormat}
+	presets, err := client.ListPresets(AllPresets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, preset := range append(presets.UserPresets, presets.UIPresets...) {
+		names = append(names, preset.Name)
+	}
+	expectedNames := []string{"my-preset", "mp4_1080p", "webm_1080p"}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("wrong presets\nwant %#v\ngot  %#v", expectedNames, names)
+	}
+}
+
+func TestPresetFormatMarshalXML(t *testing.T) {
+	format := PresetFormat{
+		Output:               "mp4",
+		VideoCodecParameters: map[string]interface{}{"coder": "0"},
+		StreamRawMap:         map[string]interface{}{"bitrate": "600k"},
+	}
+	data, err := xml.Marshal(format)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"<output>mp4</output>",
+		"<video_codec_parameters><coder>0</coder></video_codec_parameters>",
+		"<stream><bitrate>600k</bitrate></stream>",
+	} {
+		if !strings.Contains(string(data), expected) {
+			t.Errorf("missing %s in %s", expected, data)
+		}
+	}
+}
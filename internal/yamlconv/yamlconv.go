@@ -0,0 +1,323 @@
+// Package yamlconv decodes YAML documents into the types of the encodingcom
+// and elementalconductor packages, which only define JSON and XML tags.
+package yamlconv // import "github.com/NYTimes/encoding-wrapper/internal/yamlconv"
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Decoder decodes YAML documents going through JSON, guided by the type of
+// the target value.
+//
+// Keys are matched against the names in the struct tag named by Tag ("json"
+// or "xml"), or the names of the fields when they're not tagged. The name of
+// the Go field is also accepted, and any other key is rejected, so typos
+// don't go unnoticed. Nested XML names (e.g. "video_description>width") are
+// matched against nested maps, and the element wrapping a list (e.g.
+// "insertable_images" in "insertable_images>insertable_image") may hold the
+// list directly.
+//
+// Scalars are converted to the representation expected by the JSON decoder
+// for the target field, so documents can use plain YAML values (e.g. numbers
+// in string fields, or in fields with the ",string" option in their JSON
+// tag). True and False are the strings used for booleans in string fields.
+// Single values are accepted in place of lists.
+type Decoder struct {
+	Tag   string
+	True  string
+	False string
+}
+
+// Unmarshal decodes the given YAML document into v, which must be a pointer.
+func (d Decoder) Unmarshal(data []byte, v interface{}) error {
+	var doc interface{}
+	err := yaml.Unmarshal(data, &doc)
+	if err != nil {
+		return err
+	}
+	value, err := d.convert("", doc, reflect.TypeOf(v).Elem(), false)
+	if err != nil {
+		return err
+	}
+	data, err = json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// field is a node in the tree of names of a struct. Leaves hold the fields,
+// while inner nodes hold the children of nested XML names.
+type field struct {
+	key      string
+	typ      reflect.Type
+	quoted   bool
+	children map[string]*field
+}
+
+var (
+	anyType     = reflect.TypeOf((*interface{})(nil)).Elem()
+	xmlNameType = reflect.TypeOf(xml.Name{})
+)
+
+// convert converts the given YAML value to a value that can be encoded as
+// JSON and decoded into the type t. path is the path of the value in the
+// document, used in error messages.
+func (d Decoder) convert(path string, v interface{}, t reflect.Type, quoted bool) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if v == nil {
+		return nil, nil
+	}
+	if list, ok := v.([]interface{}); ok {
+		elem := anyType
+		if t.Kind() == reflect.Slice {
+			elem = t.Elem()
+		}
+		converted := make([]interface{}, len(list))
+		for i, value := range list {
+			var err error
+			converted[i], err = d.convert(fmt.Sprintf("%s[%d]", path, i), value, elem, quoted)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return converted, nil
+	}
+	if t.Kind() == reflect.Slice {
+		value, err := d.convert(path, v, t.Elem(), quoted)
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{value}, nil
+	}
+	if m, ok := v.(map[interface{}]interface{}); ok {
+		result := make(map[string]interface{}, len(m))
+		switch t.Kind() {
+		case reflect.Struct:
+			return result, d.convertStruct(path, m, d.fields(t), result)
+		case reflect.Map, reflect.Interface:
+			elem := anyType
+			if t.Kind() == reflect.Map {
+				elem = t.Elem()
+			}
+			for _, entry := range sortedEntries(m) {
+				value, err := d.convert(joinPath(path, entry.name), entry.value, elem, false)
+				if err != nil {
+					return nil, err
+				}
+				result[entry.name] = value
+			}
+			return result, nil
+		}
+		return nil, fmt.Errorf("%s: unexpected map", path)
+	}
+	if t.Kind() == reflect.Interface || t.Kind() == reflect.String {
+		return d.scalarString(v), nil
+	}
+	value := reflect.ValueOf(v)
+	if !convertible(value, t) {
+		// Values that aren't convertible are left for the JSON decoder,
+		// which either accepts them (e.g. strings in time fields) or
+		// rejects them.
+		return v, nil
+	}
+	// Scalars are converted to the target type, so typed values (e.g.
+	// encodingcom.YesNoBoolean) are encoded using their own JSON format.
+	converted := value.Convert(t).Interface()
+	if quoted {
+		return d.scalarString(converted), nil
+	}
+	return converted, nil
+}
+
+// convertStruct converts the given map using the names of the fields of a
+// struct, storing the values in result.
+func (d Decoder) convertStruct(path string, m map[interface{}]interface{}, fields map[string]*field, result map[string]interface{}) error {
+	for _, entry := range sortedEntries(m) {
+		fieldPath := joinPath(path, entry.name)
+		f, ok := fields[entry.name]
+		if !ok {
+			return fmt.Errorf("unknown field %s", fieldPath)
+		}
+		var err error
+		switch value := entry.value.(type) {
+		case nil:
+		case map[interface{}]interface{}:
+			if f.children != nil {
+				err = d.convertStruct(fieldPath, value, f.children, result)
+				break
+			}
+			result[f.key], err = d.convert(fieldPath, value, f.typ, f.quoted)
+		default:
+			if f.children == nil {
+				result[f.key], err = d.convert(fieldPath, value, f.typ, f.quoted)
+				break
+			}
+			list := listField(f)
+			if list == nil {
+				return fmt.Errorf("%s: expected a map", fieldPath)
+			}
+			result[list.key], err = d.convert(fieldPath, value, list.typ, list.quoted)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listField returns the field wrapped by the given node when it's the
+// wrapper of a list, or nil otherwise.
+func listField(f *field) *field {
+	if len(f.children) != 1 {
+		return nil
+	}
+	for _, child := range f.children {
+		if child.children == nil && child.typ.Kind() == reflect.Slice {
+			return child
+		}
+	}
+	return nil
+}
+
+// fields returns the tree of names of the fields of the given struct type.
+func (d Decoder) fields(t reflect.Type) map[string]*field {
+	fields := make(map[string]*field)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get(d.Tag)
+		if sf.PkgPath != "" || sf.Type == xmlNameType || tag == "-" {
+			continue
+		}
+		options := strings.Split(tag, ",")
+		if d.Tag == "xml" && hasAny(options[1:], "chardata", "innerxml", "comment", "any") {
+			continue
+		}
+		name := options[0]
+		if name == "" {
+			name = sf.Name
+		}
+		f := &field{
+			key:    jsonName(sf),
+			typ:    sf.Type,
+			quoted: strings.Contains(sf.Tag.Get("json"), ",string"),
+		}
+		node := fields
+		parts := strings.Split(name, ">")
+		for _, part := range parts[:len(parts)-1] {
+			parent, ok := node[part]
+			if ok && parent.children == nil {
+				// The name is taken by a field, the nested name can't
+				// be reached.
+				node = nil
+				break
+			}
+			if !ok {
+				parent = &field{children: make(map[string]*field)}
+				node[part] = parent
+			}
+			node = parent.children
+		}
+		if node != nil {
+			node[parts[len(parts)-1]] = f
+		}
+		if _, ok := fields[sf.Name]; !ok {
+			fields[sf.Name] = f
+		}
+	}
+	return fields
+}
+
+// jsonName returns the name of the given field in JSON documents.
+func jsonName(sf reflect.StructField) string {
+	if name := strings.Split(sf.Tag.Get("json"), ",")[0]; name != "" && name != "-" {
+		return name
+	}
+	return sf.Name
+}
+
+// convertible returns whether the given YAML scalar can be converted to the
+// type t without losing information.
+func convertible(value reflect.Value, t reflect.Type) bool {
+	kind := scalarKind(value.Kind())
+	if kind == "" || kind != scalarKind(t.Kind()) {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return value.Kind() != reflect.Int || value.Int() >= 0
+	}
+	return true
+}
+
+// scalarKind groups kinds that can be converted to each other without
+// losing information in YAML values.
+func scalarKind(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	case reflect.Float32, reflect.Float64:
+		return "float"
+	}
+	return ""
+}
+
+// scalarString returns the string representation of a YAML scalar.
+func (d Decoder) scalarString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case bool:
+		if v {
+			return d.True
+		}
+		return d.False
+	}
+	return fmt.Sprint(v)
+}
+
+type entry struct {
+	name  string
+	value interface{}
+}
+
+// sortedEntries returns the entries of the given YAML map, with the keys
+// converted to strings, sorted by key, so errors are reported consistently.
+func sortedEntries(m map[interface{}]interface{}) []entry {
+	entries := make([]entry, 0, len(m))
+	for key, value := range m {
+		entries = append(entries, entry{fmt.Sprint(key), value})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].name < entries[j].name })
+	return entries
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func hasAny(options []string, names ...string) bool {
+	for _, option := range options {
+		for _, name := range names {
+			if option == name {
+				return true
+			}
+		}
+	}
+	return false
+}
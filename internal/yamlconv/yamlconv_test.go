@@ -0,0 +1,222 @@
+package yamlconv
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/NYTimes/encoding-wrapper/encodingcom"
+)
+
+type xmlPreset struct {
+	XMLName     xml.Name     `xml:"preset"`
+	Href        string       `xml:"href,attr,omitempty"`
+	Name        string       `xml:"name"`
+	Width       string       `xml:"video_description>width,omitempty"`
+	Bitrate     string       `xml:"video_description>h264_settings>bitrate,omitempty"`
+	Order       int          `xml:"order,omitempty"`
+	Deinterlace bool         `xml:"deinterlace,omitempty"`
+	Enabled     string       `xml:"enabled,omitempty"`
+	Images      []xmlImage   `xml:"images>image,omitempty"`
+	Captions    []string     `xml:"caption_description_name,omitempty"`
+	Location    *xmlLocation `xml:"location,omitempty"`
+}
+
+type xmlImage struct {
+	URI string `xml:"uri"`
+}
+
+type xmlLocation struct {
+	URI      string `xml:"uri"`
+	Username string `xml:"username,omitempty"`
+}
+
+var (
+	xmlDecoder  = Decoder{Tag: "xml", True: "true", False: "false"}
+	jsonDecoder = Decoder{Tag: "json", True: "yes", False: "no"}
+)
+
+func TestUnmarshalXMLNames(t *testing.T) {
+	var tests = []struct {
+		name string
+		data string
+	}{
+		{
+			"xml names",
+			`href: /presets/1
+name: mp4
+video_description:
+  width: 1920
+  h264_settings:
+    bitrate: 5000000
+order: 2
+deinterlace: true
+enabled: yes
+images:
+  - uri: s3://bucket/logo.png
+caption_description_name: caption_1
+location:
+  uri: s3://bucket/output/
+`,
+		},
+		{
+			"wrapped list and field names",
+			`Href: /presets/1
+Name: mp4
+video_description:
+  width: "1920"
+Bitrate: "5000000"
+Order: 2
+Deinterlace: true
+Enabled: true
+images:
+  image:
+    - uri: s3://bucket/logo.png
+Captions: [caption_1]
+Location:
+  URI: s3://bucket/output/
+`,
+		},
+	}
+	expected := xmlPreset{
+		Href:        "/presets/1",
+		Name:        "mp4",
+		Width:       "1920",
+		Bitrate:     "5000000",
+		Order:       2,
+		Deinterlace: true,
+		Enabled:     "true",
+		Images:      []xmlImage{{URI: "s3://bucket/logo.png"}},
+		Captions:    []string{"caption_1"},
+		Location:    &xmlLocation{URI: "s3://bucket/output/"},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var got xmlPreset
+			err := xmlDecoder.Unmarshal([]byte(test.data), &got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("wrong preset\nwant %#v\ngot  %#v", expected, got)
+			}
+		})
+	}
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	var tests = []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{
+			"unknown field",
+			"name: mp4\nwidht: 1920\n",
+			"unknown field widht",
+		},
+		{
+			"unknown nested field",
+			"video_description:\n  h264_settings:\n    bitrat: 5000000\n",
+			"unknown field video_description.h264_settings.bitrat",
+		},
+		{
+			"unknown field in list",
+			"images:\n  - uri: s3://bucket/logo.png\n  - url: s3://bucket/other.png\n",
+			"unknown field images[1].url",
+		},
+		{
+			"scalar in place of nested names",
+			"video_description: 1920\n",
+			"video_description: expected a map",
+		},
+		{
+			"map in place of scalar",
+			"name:\n  value: mp4\n",
+			"name: unexpected map",
+		},
+		{
+			"invalid yaml",
+			"name: [\n",
+			"yaml: line 1: did not find expected node content",
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			var preset xmlPreset
+			err := xmlDecoder.Unmarshal([]byte(test.data), &preset)
+			if err == nil || err.Error() != test.expected {
+				t.Errorf("wrong error\nwant %q\ngot  %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestUnmarshalInvalidValue(t *testing.T) {
+	var preset xmlPreset
+	err := xmlDecoder.Unmarshal([]byte("order: many\n"), &preset)
+	if err == nil {
+		t.Error("unexpected <nil> error")
+	}
+}
+
+func TestUnmarshalJSONNames(t *testing.T) {
+	data := `output: advanced_hls
+audio_sample_rate: 44100
+audio_volume: "100"
+framerate: 29.97
+crop_left: 10
+keyframe: [90, 300]
+segment_duration: 6
+two_pass: yes
+turbo: no
+logo:
+  logo_source: http://example.com/logo.png
+  logo_x: 10
+video_codec_parameters:
+  level: 31
+stream:
+  size: 640x360
+  audio_only: yes
+  framerate: 30
+  video_codec_parameters:
+    level: 30
+text_overlay:
+  - text: hello
+    align_center: yes
+`
+	expected := encodingcom.Format{
+		Output:               []string{"advanced_hls"},
+		AudioSampleRate:      44100,
+		AudioVolume:          100,
+		Framerate:            "29.97",
+		CropLeft:             10,
+		Keyframe:             []string{"90", "300"},
+		SegmentDuration:      6,
+		TwoPass:              true,
+		Logo:                 &encodingcom.Logo{LogoSourceURL: "http://example.com/logo.png", LogoX: 10},
+		VideoCodecParameters: encodingcom.VideoCodecParameters{Level: "31"},
+		Stream: []encodingcom.Stream{
+			{Size: "640x360", AudioOnly: true, Framerate: 30, VideoCodecParametersRaw: map[string]interface{}{"level": "30"}},
+		},
+		TextOverlay: []encodingcom.TextOverlay{{Text: []string{"hello"}, AlignCenter: true}},
+	}
+	var got encodingcom.Format
+	err := jsonDecoder.Unmarshal([]byte(data), &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong format\nwant %#v\ngot  %#v", expected, got)
+	}
+}
+
+func TestUnmarshalJSONInvalidValue(t *testing.T) {
+	var format encodingcom.Format
+	err := jsonDecoder.Unmarshal([]byte("audio_sample_rate: -1\n"), &format)
+	if err == nil {
+		t.Error("unexpected <nil> error")
+	}
+}
@@ -0,0 +1,192 @@
+package presetsync
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/NYTimes/encoding-wrapper/elementalconductor"
+)
+
+// ElementalConductor syncs the presets in an Elemental Conductor cluster with
+// a set of local definitions.
+//
+// Presets holds the local definitions, indexed by preset name. Remote presets
+// are matched by name, and updated in place, so they keep their ids (and the
+// references to them in the Preset field of StreamAssembly) stable. The Href
+// and Permalink are assigned by the server, so they're ignored, both in local
+// definitions and when comparing (matching "conductor presets export", which
+// clears them).
+type ElementalConductor struct {
+	Client  *elementalconductor.Client
+	Presets map[string]elementalconductor.Preset
+}
+
+// NewElementalConductor creates a syncer for the given client, loading the
+// local definitions from the given directory (see
+// LoadElementalConductorPresets).
+func NewElementalConductor(client *elementalconductor.Client, dir string) (*ElementalConductor, error) {
+	presets, err := LoadElementalConductorPresets(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &ElementalConductor{Client: client, Presets: presets}, nil
+}
+
+// LoadElementalConductorPresets loads the preset definitions in the given
+// directory. Each preset is defined in a XML file, in the format used by the
+// Conductor API (which is also the format of "conductor presets export").
+// The name of the preset defaults to the name of the file, without the .xml
+// extension, when the definition doesn't include one.
+func LoadElementalConductorPresets(dir string) (map[string]elementalconductor.Preset, error) {
+	files, err := readDir(dir, ".xml")
+	if err != nil {
+		return nil, err
+	}
+	presets := make(map[string]elementalconductor.Preset, len(files))
+	for fileName, data := range files {
+		var preset elementalconductor.Preset
+		err = xml.Unmarshal(data, &preset)
+		if err != nil {
+			return nil, fmt.Errorf("invalid definition for preset %s: %s", fileName, err)
+		}
+		if preset.Name == "" {
+			preset.Name = fileName
+		}
+		if _, ok := presets[preset.Name]; ok {
+			return nil, fmt.Errorf("duplicate definition for preset %s", preset.Name)
+		}
+		preset.Href = ""
+		preset.Permalink = ""
+		presets[preset.Name] = preset
+	}
+	return presets, nil
+}
+
+// Plan compares the local definitions with the presets in the cluster. It
+// returns an error when multiple presets in the cluster share a name, since
+// they can't be matched to a single definition, or when a preset has no
+// href, as its id is needed for updating and deleting it.
+func (s *ElementalConductor) Plan() (*Plan, error) {
+	list, err := s.Client.GetPresets()
+	if err != nil {
+		return nil, err
+	}
+	var plan Plan
+	remote := make(map[string]string, len(list.Presets))
+	for _, current := range list.Presets {
+		if current.Href == "" {
+			return nil, fmt.Errorf("preset %s in the cluster has no href", current.Name)
+		}
+		id := path.Base(current.Href)
+		if other, ok := remote[current.Name]; ok {
+			return nil, fmt.Errorf("multiple presets named %s in the cluster (ids %s and %s)", current.Name, other, id)
+		}
+		remote[current.Name] = id
+		desired, ok := s.Presets[current.Name]
+		if !ok {
+			plan.Changes = append(plan.Changes, Change{Action: ActionDelete, Name: current.Name, ID: id})
+			continue
+		}
+		diff := diffFields(flattenPreset(current), flattenPreset(desired))
+		if len(diff) > 0 {
+			plan.Changes = append(plan.Changes, Change{Action: ActionUpdate, Name: current.Name, ID: id, Diff: diff})
+		}
+	}
+	for name := range s.Presets {
+		if _, ok := remote[name]; !ok {
+			plan.Changes = append(plan.Changes, Change{Action: ActionCreate, Name: name})
+		}
+	}
+	plan.sort()
+	return &plan, nil
+}
+
+// Apply executes the changes in the given plan. Apply stops at the first
+// failure, leaving the remaining changes unapplied.
+func (s *ElementalConductor) Apply(plan *Plan) error {
+	for _, change := range plan.Changes {
+		var err error
+		switch change.Action {
+		case ActionCreate, ActionUpdate:
+			preset, ok := s.Presets[change.Name]
+			if !ok {
+				return fmt.Errorf("no local definition for preset %s", change.Name)
+			}
+			preset.Href = ""
+			preset.Permalink = ""
+			if change.Action == ActionCreate {
+				_, err = s.Client.CreatePreset(&preset)
+			} else {
+				_, err = s.Client.UpdatePreset(change.ID, &preset)
+			}
+		case ActionDelete:
+			err = s.Client.DeletePreset(change.ID)
+		default:
+			err = fmt.Errorf("unknown action %q", change.Action)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s preset %s: %s", change.Action, change.Name, err)
+		}
+	}
+	return nil
+}
+
+// flattenPreset converts the given preset to a flat map of field paths to
+// values, using the path of the field in the XML representation of the
+// preset (e.g. "video_description.h264_settings.bitrate"). The Href and
+// Permalink are not included.
+func flattenPreset(preset elementalconductor.Preset) map[string]string {
+	preset.Href = ""
+	preset.Permalink = ""
+	fields := make(map[string]string)
+	flattenStruct("", reflect.ValueOf(preset), fields)
+	return fields
+}
+
+func flattenStruct(prefix string, v reflect.Value, fields map[string]string) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("xml"), ",")[0]
+		if name == "" || name == "-" || t.Field(i).Type == reflect.TypeOf(xml.Name{}) {
+			continue
+		}
+		fieldPath := prefix + strings.Replace(name, ">", ".", -1)
+		field := v.Field(i)
+		if field.Kind() == reflect.Ptr {
+			if field.IsNil() {
+				continue
+			}
+			field = field.Elem()
+		}
+		switch field.Kind() {
+		case reflect.Struct:
+			flattenStruct(fieldPath+".", field, fields)
+		case reflect.String:
+			setField(fields, fieldPath, field.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			setField(fields, fieldPath, strconv.FormatInt(field.Int(), 10))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			setField(fields, fieldPath, strconv.FormatUint(field.Uint(), 10))
+		case reflect.Float32, reflect.Float64:
+			setField(fields, fieldPath, strconv.FormatFloat(field.Float(), 'f', -1, 64))
+		case reflect.Bool:
+			if field.Bool() {
+				setField(fields, fieldPath, "true")
+			}
+		}
+	}
+}
+
+// setField stores the given value, skipping empty values, "no" and "0",
+// which are equivalent to unset fields in Conductor presets.
+func setField(fields map[string]string, path, value string) {
+	switch value {
+	case "", "no", "0":
+		return
+	}
+	fields[path] = value
+}
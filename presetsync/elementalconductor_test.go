@@ -0,0 +1,165 @@
+package presetsync
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/NYTimes/encoding-wrapper/elementalconductor"
+	"github.com/NYTimes/encoding-wrapper/elementalconductor/elementalconductortest"
+)
+
+func TestElementalConductorSync(t *testing.T) {
+	server := elementalconductortest.NewServer("myuser", "secret-key")
+	defer server.Close()
+	server.AddPreset(elementalconductor.Preset{Name: "mp4_720p", Permalink: "mp4_720p", Container: "mp4", Height: "720", VideoCodec: "h.264"})
+	server.AddPreset(elementalconductor.Preset{Name: "mp4_1080p", Container: "mp4", Height: "1080", VideoCodec: "h.264", VideoBitrate: "4000000"})
+	server.AddPreset(elementalconductor.Preset{Name: "old", Container: "mp4"})
+	dir, cleanup := writeFiles(t, map[string]string{
+		"mp4_720p.xml": `<preset href="/presets/99">
+  <name>mp4_720p</name>
+  <container>mp4</container>
+  <video_description>
+    <height>720</height>
+    <codec>h.264</codec>
+  </video_description>
+</preset>`,
+		"mp4_1080p.xml": `<preset>
+  <name>mp4_1080p</name>
+  <container>mp4</container>
+  <video_description>
+    <height>1080</height>
+    <codec>h.264</codec>
+    <h264_settings>
+      <bitrate>5000000</bitrate>
+      <profile>High</profile>
+    </h264_settings>
+  </video_description>
+</preset>`,
+		"hevc.xml": `<preset>
+  <container>mp4</container>
+  <video_description>
+    <codec>h.265</codec>
+    <h265_settings>
+      <bitrate>3000000</bitrate>
+    </h265_settings>
+  </video_description>
+</preset>`,
+		"README.md": "not a preset",
+	})
+	defer cleanup()
+
+	client := server.Client()
+	syncer, err := NewElementalConductor(client, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := syncer.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Plan{Changes: []Change{
+		{Action: ActionCreate, Name: "hevc"},
+		{Action: ActionUpdate, Name: "mp4_1080p", ID: "2", Diff: []FieldDiff{
+			{Field: "video_description.h264_settings.bitrate", Current: "4000000", Desired: "5000000"},
+			{Field: "video_description.h264_settings.profile", Desired: "High"},
+		}},
+		{Action: ActionDelete, Name: "old", ID: "3"},
+	}}
+	if !reflect.DeepEqual(plan, expected) {
+		t.Fatalf("wrong plan\nwant %#v\ngot  %#v", expected, plan)
+	}
+
+	err = syncer.Apply(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err = syncer.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Errorf("unexpected changes after applying the plan:\n%s", plan)
+	}
+	updated, err := client.GetPreset("2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "mp4_1080p" || updated.VideoBitrate != "5000000" {
+		t.Errorf("wrong preset after update: %#v", updated)
+	}
+	if _, err = client.GetPreset("3"); err == nil {
+		t.Error("unexpected <nil> error getting deleted preset")
+	}
+	created, err := client.GetPreset("4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Name != "hevc" || created.H265Settings == nil || created.H265Settings.Bitrate != "3000000" {
+		t.Errorf("wrong created preset: %#v", created)
+	}
+}
+
+func TestLoadElementalConductorPresetsDuplicate(t *testing.T) {
+	dir, cleanup := writeFiles(t, map[string]string{
+		"a.xml": `<preset><name>mp4</name></preset>`,
+		"b.xml": `<preset><name>mp4</name></preset>`,
+	})
+	defer cleanup()
+	_, err := LoadElementalConductorPresets(dir)
+	if err == nil || err.Error() != "duplicate definition for preset mp4" {
+		t.Errorf("wrong error: %v", err)
+	}
+}
+
+func TestElementalConductorPlanDuplicateRemote(t *testing.T) {
+	server := elementalconductortest.NewServer("myuser", "secret-key")
+	defer server.Close()
+	server.AddPreset(elementalconductor.Preset{Name: "mp4", Container: "mp4"})
+	server.AddPreset(elementalconductor.Preset{Name: "hls", Container: "m3u8"})
+	server.AddPreset(elementalconductor.Preset{Name: "mp4", Container: "mp4", Height: "720"})
+	syncer := ElementalConductor{Client: server.Client(), Presets: map[string]elementalconductor.Preset{}}
+	_, err := syncer.Plan()
+	expected := "multiple presets named mp4 in the cluster (ids 1 and 3)"
+	if err == nil || err.Error() != expected {
+		t.Errorf("wrong error\nwant %q\ngot  %v", expected, err)
+	}
+}
+
+func TestElementalConductorPlanMissingHref(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(`<preset_list><preset><name>mp4</name></preset></preset_list>`))
+	}))
+	defer server.Close()
+	client := elementalconductor.NewClient(server.URL, "myuser", "secret-key", 45, "", "", "")
+	syncer := ElementalConductor{Client: client, Presets: map[string]elementalconductor.Preset{"mp4": {Name: "mp4"}}}
+	_, err := syncer.Plan()
+	expected := "preset mp4 in the cluster has no href"
+	if err == nil || err.Error() != expected {
+		t.Errorf("wrong error\nwant %q\ngot  %v", expected, err)
+	}
+}
+
+func TestFlattenPreset(t *testing.T) {
+	preset := elementalconductor.Preset{
+		Name:         "hevc",
+		Href:         "/presets/1",
+		Permalink:    "hevc",
+		Container:    "mp4",
+		Width:        "1920",
+		VideoBitrate: "0",
+		H265Settings: &elementalconductor.H265Settings{Bitrate: "3000000", Profile: elementalconductor.H265Profile("Main")},
+	}
+	expected := map[string]string{
+		"name":                    "hevc",
+		"container":               "mp4",
+		"video_description.width": "1920",
+		"video_description.h265_settings.bitrate": "3000000",
+		"video_description.h265_settings.profile": "Main",
+	}
+	if got := flattenPreset(preset); !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong fields\nwant %#v\ngot  %#v", expected, got)
+	}
+}
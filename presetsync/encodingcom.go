@@ -0,0 +1,205 @@
+package presetsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/NYTimes/encoding-wrapper/encodingcom"
+	"github.com/NYTimes/encoding-wrapper/internal/yamlconv"
+)
+
+// EncodingCom syncs user presets in an Encoding.com account with a set of
+// local definitions. UI (standard) presets are never modified.
+//
+// Presets holds the local definitions, indexed by preset name. Definitions
+// are the encodingcom.Format used when saving the preset. Both the local
+// definitions and the remote presets are converted to their canonical
+// representation (see encodingcom.NewPresetFormat and
+// encodingcom.PresetFormat.Format) before being compared field by field, so
+// every difference, including flags being turned off, is part of the plan.
+type EncodingCom struct {
+	Client  *encodingcom.Client
+	Presets map[string]encodingcom.Format
+}
+
+// NewEncodingCom creates a syncer for the given client, loading the local
+// definitions from the given directory (see LoadEncodingComPresets).
+func NewEncodingCom(client *encodingcom.Client, dir string) (*EncodingCom, error) {
+	presets, err := LoadEncodingComPresets(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &EncodingCom{Client: client, Presets: presets}, nil
+}
+
+// formatYAML decodes YAML preset definitions, accepting plain YAML values
+// (e.g. "two_pass: yes" or "audio_sample_rate: 44100").
+var formatYAML = yamlconv.Decoder{Tag: "json", True: "yes", False: "no"}
+
+// LoadEncodingComPresets loads the preset definitions in the given directory.
+// Each preset is defined in a YAML file (with the .yaml or .yml extension)
+// containing an encodingcom.Format, using the same keys as the API (e.g.
+// video_codec or two_pass), and the name of the file, without the extension,
+// is the name of the preset. As YAML is a superset of JSON, definitions may
+// also be written in JSON, in files with the .json extension.
+//
+// Definitions using unknown keys or fields that can't be stored in presets
+// (e.g. destination) are rejected.
+func LoadEncodingComPresets(dir string) (map[string]encodingcom.Format, error) {
+	files, err := readDir(dir, ".yaml", ".yml", ".json")
+	if err != nil {
+		return nil, err
+	}
+	presets := make(map[string]encodingcom.Format, len(files))
+	for name, data := range files {
+		var format encodingcom.Format
+		err = formatYAML.Unmarshal(data, &format)
+		if err == nil {
+			_, err = encodingcom.NewPresetFormat(format)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid definition for preset %s: %s", name, err)
+		}
+		presets[name] = format
+	}
+	return presets, nil
+}
+
+// Plan compares the local definitions with the user presets in the account.
+func (s *EncodingCom) Plan() (*Plan, error) {
+	list, err := s.Client.ListPresets(encodingcom.UserPresets)
+	if err != nil {
+		return nil, err
+	}
+	var plan Plan
+	remote := make(map[string]bool, len(list.UserPresets))
+	for _, item := range list.UserPresets {
+		remote[item.Name] = true
+		desired, ok := s.Presets[item.Name]
+		if !ok {
+			plan.Changes = append(plan.Changes, Change{Action: ActionDelete, Name: item.Name})
+			continue
+		}
+		preset, err := s.Client.GetPreset(item.Name)
+		if err != nil {
+			return nil, err
+		}
+		current, err := preset.Format.Format()
+		if err != nil {
+			return nil, fmt.Errorf("invalid remote preset %s: %s", item.Name, err)
+		}
+		desired, err = canonicalFormat(desired)
+		if err != nil {
+			return nil, fmt.Errorf("invalid definition for preset %s: %s", item.Name, err)
+		}
+		if diff := diffFields(flattenFormat(current), flattenFormat(desired)); len(diff) > 0 {
+			plan.Changes = append(plan.Changes, Change{Action: ActionUpdate, Name: item.Name, Diff: diff})
+		}
+	}
+	for name := range s.Presets {
+		if !remote[name] {
+			plan.Changes = append(plan.Changes, Change{Action: ActionCreate, Name: name})
+		}
+	}
+	plan.sort()
+	return &plan, nil
+}
+
+// Apply executes the changes in the given plan. Updates are applied by saving
+// the preset again, under the same name. Apply stops at the first failure,
+// leaving the remaining changes unapplied.
+func (s *EncodingCom) Apply(plan *Plan) error {
+	for _, change := range plan.Changes {
+		var err error
+		switch change.Action {
+		case ActionCreate, ActionUpdate:
+			format, ok := s.Presets[change.Name]
+			if !ok {
+				return fmt.Errorf("no local definition for preset %s", change.Name)
+			}
+			_, err = s.Client.SavePreset(change.Name, format)
+		case ActionDelete:
+			_, err = s.Client.DeletePreset(change.Name)
+		default:
+			err = fmt.Errorf("unknown action %q", change.Action)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to %s preset %s: %s", change.Action, change.Name, err)
+		}
+	}
+	return nil
+}
+
+// canonicalFormat returns the given format as it's represented once stored
+// in a preset.
+func canonicalFormat(format encodingcom.Format) (encodingcom.Format, error) {
+	preset, err := encodingcom.NewPresetFormat(format)
+	if err != nil {
+		return encodingcom.Format{}, err
+	}
+	return preset.Format()
+}
+
+// flattenFormat converts the given format to a flat map of field paths to
+// values, using the names of the fields in the API (e.g. "logo.logo_x" or
+// "stream.0.size"). Values are represented as they're sent to the API, so
+// flags are either "yes" or "no".
+func flattenFormat(format encodingcom.Format) map[string]string {
+	fields := make(map[string]string)
+	flattenValue("", reflect.ValueOf(format), fields)
+	return fields
+}
+
+func flattenValue(path string, v reflect.Value, fields map[string]string) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			flattenValue(path, v.Elem(), fields)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+			if name == "" || name == "-" {
+				continue
+			}
+			flattenValue(joinPath(path, name), v.Field(i), fields)
+		}
+	case reflect.Map:
+		for _, key := range v.MapKeys() {
+			flattenValue(joinPath(path, fmt.Sprint(key.Interface())), v.MapIndex(key), fields)
+		}
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.String {
+			values := make([]string, v.Len())
+			for i := range values {
+				values[i] = v.Index(i).String()
+			}
+			fields[path] = strings.Join(values, ",")
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			flattenValue(joinPath(path, strconv.Itoa(i)), v.Index(i), fields)
+		}
+	default:
+		data, err := json.Marshal(v.Interface())
+		if err != nil {
+			return
+		}
+		value := string(data)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		fields[path] = value
+	}
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,209 @@
+package presetsync
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/NYTimes/encoding-wrapper/encodingcom"
+	"github.com/NYTimes/encoding-wrapper/encodingcom/encodingcomtest"
+)
+
+func writeFiles(t *testing.T, files map[string]string) (string, func()) {
+	dir, err := ioutil.TempDir("", "presetsync")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		if err != nil {
+			os.RemoveAll(dir)
+			t.Fatal(err)
+		}
+	}
+	return dir, func() { os.RemoveAll(dir) }
+}
+
+func TestEncodingComSync(t *testing.T) {
+	server := encodingcomtest.NewServer()
+	defer server.Close()
+	client := server.Client()
+	for name, format := range map[string]encodingcom.Format{
+		"mp4_720p":  {Output: []string{"mp4"}, Size: "1280x720", Bitrate: "2500k", VideoCodec: "libx264", TwoPass: true},
+		"mp4_1080p": {Output: []string{"mp4"}, Size: "1920x1080", Bitrate: "4000k", VideoCodec: "libx264"},
+		"old":       {Output: []string{"webm"}},
+	} {
+		if _, err := client.SavePreset(name, format); err != nil {
+			t.Fatal(err)
+		}
+	}
+	server.AddPreset(encodingcom.Preset{Name: "ui_preset", Output: "mp4", Format: encodingcom.PresetFormat{Output: "mp4"}})
+	dir, cleanup := writeFiles(t, map[string]string{
+		"mp4_720p.yaml": `output: mp4
+size: 1280x720
+bitrate: 2500k
+video_codec: libx264
+two_pass: no
+`,
+		"mp4_1080p.yml": `output: [mp4]
+size: 1920x1080
+bitrate: 5000k
+video_codec: libx264
+two_pass: yes
+`,
+		"hls.json":  `{"output": ["advanced_hls"], "stream": [{"size": "640x360", "bitrate": "800k"}]}`,
+		"README.md": "not a preset",
+	})
+	defer cleanup()
+
+	syncer, err := NewEncodingCom(client, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := syncer.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &Plan{Changes: []Change{
+		{Action: ActionCreate, Name: "hls"},
+		{Action: ActionUpdate, Name: "mp4_1080p", Diff: []FieldDiff{
+			{Field: "bitrate", Current: "4000k", Desired: "5000k"},
+			{Field: "two_pass", Current: "no", Desired: "yes"},
+		}},
+		{Action: ActionUpdate, Name: "mp4_720p", Diff: []FieldDiff{
+			{Field: "two_pass", Current: "yes", Desired: "no"},
+		}},
+		{Action: ActionDelete, Name: "old"},
+	}}
+	if !reflect.DeepEqual(plan, expected) {
+		t.Fatalf("wrong plan\nwant %#v\ngot  %#v", expected, plan)
+	}
+	expectedOutput := `+ hls
+~ mp4_1080p
+    bitrate: "4000k" => "5000k"
+    two_pass: "no" => "yes"
+~ mp4_720p
+    two_pass: "yes" => "no"
+- old
+`
+	if plan.String() != expectedOutput {
+		t.Errorf("wrong plan output\nwant %q\ngot  %q", expectedOutput, plan.String())
+	}
+
+	err = syncer.Apply(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err = syncer.Plan()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !plan.Empty() {
+		t.Errorf("unexpected changes after applying the plan:\n%s", plan)
+	}
+	if _, err = client.GetPreset("ui_preset"); err != nil {
+		t.Errorf("unexpected error getting ui preset: %s", err)
+	}
+	preset, err := client.GetPreset("hls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedStream := []encodingcom.Stream{{Size: "640x360", Bitrate: "800k"}}
+	if !reflect.DeepEqual(preset.Format.Stream(), expectedStream) {
+		t.Errorf("wrong stream\nwant %#v\ngot  %#v", expectedStream, preset.Format.Stream())
+	}
+}
+
+func TestEncodingComApplyError(t *testing.T) {
+	server := encodingcomtest.NewServer()
+	defer server.Close()
+	server.SetError("SavePreset", "something went wrong")
+	syncer := EncodingCom{
+		Client:  server.Client(),
+		Presets: map[string]encodingcom.Format{"mp4": {Output: []string{"mp4"}}},
+	}
+	err := syncer.Apply(&Plan{Changes: []Change{{Action: ActionCreate, Name: "mp4"}}})
+	if err == nil {
+		t.Fatal("unexpected <nil> error")
+	}
+	err = syncer.Apply(&Plan{Changes: []Change{{Action: ActionUpdate, Name: "unknown"}}})
+	if err == nil || err.Error() != "no local definition for preset unknown" {
+		t.Errorf("wrong error: %v", err)
+	}
+}
+
+func TestLoadEncodingComPresetsInvalid(t *testing.T) {
+	var tests = []struct {
+		name     string
+		files    map[string]string
+		expected string
+	}{
+		{
+			"broken json",
+			map[string]string{"broken.json": `{"output": "mp4"`},
+			"invalid definition for preset broken: yaml: line 1: did not find expected ',' or '}'",
+		},
+		{
+			"unsupported field",
+			map[string]string{"mp4.yaml": "output: mp4\ndestination: s3://mybucket/\n"},
+			"invalid definition for preset mp4: fields not supported in presets: destination",
+		},
+		{
+			"unknown field",
+			map[string]string{"mp4.yaml": "output: mp4\nvideo_codc: libx264\n"},
+			"invalid definition for preset mp4: unknown field video_codc",
+		},
+		{
+			"duplicate",
+			map[string]string{"mp4.yaml": "output: mp4\n", "mp4.json": `{"output": "mp4"}`},
+			"duplicate definition for preset mp4",
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			dir, cleanup := writeFiles(t, test.files)
+			defer cleanup()
+			_, err := LoadEncodingComPresets(dir)
+			if err == nil || err.Error() != test.expected {
+				t.Errorf("wrong error\nwant %q\ngot  %v", test.expected, err)
+			}
+		})
+	}
+}
+
+func TestFlattenFormat(t *testing.T) {
+	format := encodingcom.Format{
+		Output:          []string{"advanced_hls"},
+		Keyframe:        []string{"90", "300"},
+		SegmentDuration: 6,
+		Logo:            &encodingcom.Logo{LogoSourceURL: "http://logo.png", LogoX: 10},
+		TwoPass:         true,
+		Stream: []encodingcom.Stream{
+			{Size: "640x360", VideoCodecParametersRaw: map[string]interface{}{"level": "30"}},
+		},
+	}
+	fields := flattenFormat(format)
+	expected := map[string]string{
+		"output":                                "advanced_hls",
+		"keyframe":                              "90,300",
+		"segment_duration":                      "6",
+		"logo.logo_source":                      "http://logo.png",
+		"logo.logo_x":                           "10",
+		"two_pass":                              "yes",
+		"turbo":                                 "no",
+		"stream.0.size":                         "640x360",
+		"stream.0.video_codec_parameters.level": "30",
+		"stream.0.audio_only":                   "no",
+	}
+	for field, value := range expected {
+		if fields[field] != value {
+			t.Errorf("wrong value for %s\nwant %q\ngot  %q", field, value, fields[field])
+		}
+	}
+	if _, ok := fields["metadata.title"]; ok {
+		t.Error("unexpected field for nil metadata")
+	}
+}
@@ -0,0 +1,145 @@
+// Package presetsync reconciles preset definitions kept in a local directory
+// (typically versioned in git) with the presets stored in the transcoding
+// providers.
+//
+// Syncing is done in two steps: Plan compares the local definitions with the
+// remote presets and returns the list of changes needed for making them
+// match, and Apply executes those changes. Plans can be printed for review
+// before being applied, allowing dry runs.
+package presetsync // import "github.com/NYTimes/encoding-wrapper/presetsync"
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Action is the kind of change applied to a preset.
+type Action string
+
+const (
+	// ActionCreate is used for presets that are defined locally but don't
+	// exist in the provider.
+	ActionCreate = Action("create")
+
+	// ActionUpdate is used for presets that exist in the provider, but
+	// differ from the local definition.
+	ActionUpdate = Action("update")
+
+	// ActionDelete is used for presets that exist in the provider, but are
+	// not defined locally.
+	ActionDelete = Action("delete")
+)
+
+// Change is a single change in a Plan.
+type Change struct {
+	Action Action
+	Name   string
+
+	// ID is the identifier of the remote preset, for providers that don't
+	// identify presets by name. It's not set for creations.
+	ID string
+
+	// Diff lists the fields that differ between the remote preset and the
+	// local definition. It's only set for updates.
+	Diff []FieldDiff
+}
+
+// FieldDiff represents a field that differs between the remote preset and
+// the local definition. Nested fields use dotted paths (e.g. "logo.logo_x"
+// or "stream.0.size").
+type FieldDiff struct {
+	Field   string
+	Current string
+	Desired string
+}
+
+// Plan is the list of changes required for making the presets in a provider
+// match the local definitions. Changes are sorted by preset name.
+type Plan struct {
+	Changes []Change
+}
+
+// Empty returns whether the plan has no changes, meaning that the provider is
+// in sync with the local definitions.
+func (p *Plan) Empty() bool {
+	return len(p.Changes) == 0
+}
+
+// String returns a human readable representation of the plan, with one line
+// per change, prefixed by "+" for creations, "~" for updates and "-" for
+// deletions. Updates are followed by the list of changed fields.
+func (p *Plan) String() string {
+	if p.Empty() {
+		return "no changes\n"
+	}
+	var buf bytes.Buffer
+	for _, change := range p.Changes {
+		switch change.Action {
+		case ActionCreate:
+			fmt.Fprintf(&buf, "+ %s\n", change.Name)
+		case ActionUpdate:
+			fmt.Fprintf(&buf, "~ %s\n", change.Name)
+			for _, diff := range change.Diff {
+				fmt.Fprintf(&buf, "    %s: %q => %q\n", diff.Field, diff.Current, diff.Desired)
+			}
+		case ActionDelete:
+			fmt.Fprintf(&buf, "- %s\n", change.Name)
+		}
+	}
+	return buf.String()
+}
+
+func (p *Plan) sort() {
+	sort.Slice(p.Changes, func(i, j int) bool {
+		return p.Changes[i].Name < p.Changes[j].Name
+	})
+}
+
+// diffFields compares two sets of flattened fields, returning the fields that
+// differ, sorted by name.
+func diffFields(current, desired map[string]string) []FieldDiff {
+	var diff []FieldDiff
+	for field, value := range desired {
+		if current[field] != value {
+			diff = append(diff, FieldDiff{Field: field, Current: current[field], Desired: value})
+		}
+	}
+	for field, value := range current {
+		if _, ok := desired[field]; !ok {
+			diff = append(diff, FieldDiff{Field: field, Current: value})
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i].Field < diff[j].Field
+	})
+	return diff
+}
+
+// readDir reads the files with the given extensions in dir, returning their
+// content indexed by the file name without the extension. Files that only
+// differ in the extension are reported as duplicate definitions.
+func readDir(dir string, exts ...string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	for _, ext := range exts {
+		paths, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			name := strings.TrimSuffix(filepath.Base(path), ext)
+			if _, ok := files[name]; ok {
+				return nil, fmt.Errorf("duplicate definition for preset %s", name)
+			}
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			files[name] = data
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,194 @@
+package transcoding
+
+import (
+	"strconv"
+
+	"github.com/NYTimes/encoding-wrapper/elementalconductor"
+)
+
+// ElementalConductorName is the value of ProviderName in JobStatus for jobs
+// in the Elemental Conductor provider.
+const ElementalConductorName = "elementalconductor"
+
+// ElementalConductorProvider is the Provider implementation backed by an
+// elementalconductor.Client.
+type ElementalConductorProvider struct {
+	Client *elementalconductor.Client
+}
+
+// NewElementalConductorProvider creates a Provider that uses the given
+// client.
+func NewElementalConductorProvider(client *elementalconductor.Client) *ElementalConductorProvider {
+	return &ElementalConductorProvider{Client: client}
+}
+
+// Submit creates a new job in Elemental Conductor, with one stream assembly
+// and one output group for each output in the job. Outputs without a
+// destination are saved to the Destination defined in the client.
+func (p *ElementalConductorProvider) Submit(job *Job) (*JobStatus, error) {
+	createdJob, err := p.Client.CreateJob(p.newJob(job))
+	if err != nil {
+		return nil, err
+	}
+	return p.jobStatus(createdJob), nil
+}
+
+func (p *ElementalConductorProvider) newJob(job *Job) *elementalconductor.Job {
+	conductorJob := elementalconductor.Job{
+		Input: []elementalconductor.Input{
+			{FileInput: p.location(job.Source)},
+		},
+	}
+	for i, output := range job.Outputs {
+		streamAssemblyName := "stream_" + strconv.Itoa(i+1)
+		conductorJob.StreamAssembly = append(conductorJob.StreamAssembly, elementalconductor.StreamAssembly{
+			Name:   streamAssemblyName,
+			Preset: output.Preset,
+		})
+		destination := output.Destination
+		if destination == "" {
+			destination = p.Client.Destination
+		}
+		location := p.location(destination)
+		outputGroup := elementalconductor.OutputGroup{
+			Order: i + 1,
+			Output: []elementalconductor.Output{
+				{
+					StreamAssemblyName: streamAssemblyName,
+					Order:              1,
+					Container:          elementalconductor.Container(output.Container),
+				},
+			},
+		}
+		if outputGroup.Output[0].Container == elementalconductor.AppleHTTPLiveStreaming {
+			outputGroup.Type = elementalconductor.AppleLiveOutputGroupType
+			outputGroup.AppleLiveGroupSettings = &elementalconductor.AppleLiveGroupSettings{Destination: &location}
+		} else {
+			outputGroup.Type = elementalconductor.FileOutputGroupType
+			outputGroup.FileGroupSettings = &elementalconductor.FileGroupSettings{Destination: &location}
+		}
+		conductorJob.OutputGroup = append(conductorJob.OutputGroup, outputGroup)
+	}
+	return &conductorJob
+}
+
+func (p *ElementalConductorProvider) location(uri string) elementalconductor.Location {
+	return elementalconductor.Location{
+		URI:      uri,
+		Username: p.Client.AccessKeyID,
+		Password: p.Client.SecretAccessKey,
+	}
+}
+
+// Status returns the status of the given job in Elemental Conductor.
+func (p *ElementalConductorProvider) Status(jobID string) (*JobStatus, error) {
+	job, err := p.Client.GetJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+	return p.jobStatus(job), nil
+}
+
+func (p *ElementalConductorProvider) jobStatus(job *elementalconductor.Job) *JobStatus {
+	jobStatus := JobStatus{
+		ProviderName:   ElementalConductorName,
+		ProviderJobID:  job.GetID(),
+		Status:         elementalConductorStatus(job),
+		ProviderStatus: string(job.Status),
+		Progress:       float64(job.PercentComplete),
+	}
+	streamAssemblies := make(map[string]elementalconductor.StreamAssembly, len(job.StreamAssembly))
+	for _, streamAssembly := range job.StreamAssembly {
+		streamAssemblies[streamAssembly.Name] = streamAssembly
+	}
+	for _, outputGroup := range job.OutputGroup {
+		var destination string
+		if outputGroup.FileGroupSettings != nil && outputGroup.FileGroupSettings.Destination != nil {
+			destination = outputGroup.FileGroupSettings.Destination.URI
+		} else if outputGroup.AppleLiveGroupSettings != nil && outputGroup.AppleLiveGroupSettings.Destination != nil {
+			destination = outputGroup.AppleLiveGroupSettings.Destination.URI
+		}
+		for _, output := range outputGroup.Output {
+			outputFile := OutputFile{
+				Destination: destination,
+				Container:   string(output.Container),
+			}
+			if output.FullURI != "" {
+				outputFile.Destination = output.FullURI
+			}
+			if videoDescription := streamAssemblies[output.StreamAssemblyName].VideoDescription; videoDescription != nil {
+				outputFile.VideoCodec = videoDescription.Codec
+				outputFile.Width = videoDescription.GetWidth()
+				outputFile.Height = videoDescription.GetHeight()
+				outputFile.Bitrate = videoBitrate(videoDescription)
+			}
+			jobStatus.Outputs = append(jobStatus.Outputs, outputFile)
+		}
+	}
+	return &jobStatus
+}
+
+func videoBitrate(videoDescription *elementalconductor.StreamVideoDescription) int64 {
+	var bitrate string
+	switch settings := videoDescription.CodecSettings().(type) {
+	case *elementalconductor.H264Settings:
+		bitrate = settings.Bitrate
+	case *elementalconductor.H265Settings:
+		bitrate = settings.Bitrate
+	}
+	value, _ := strconv.ParseInt(bitrate, 10, 64)
+	return value
+}
+
+// Cancel cancels the given job in Elemental Conductor.
+func (p *ElementalConductorProvider) Cancel(jobID string) error {
+	_, err := p.Client.CancelJob(jobID)
+	return err
+}
+
+// ListPresets returns the presets available in Elemental Conductor.
+func (p *ElementalConductorProvider) ListPresets() ([]Preset, error) {
+	presetList, err := p.Client.GetPresets()
+	if err != nil {
+		return nil, err
+	}
+	var presets []Preset
+	for _, preset := range presetList.Presets {
+		presets = append(presets, Preset{
+			Name:        preset.Name,
+			Description: preset.Description,
+			Container:   preset.Container,
+			VideoCodec:  preset.VideoCodec,
+		})
+	}
+	return presets, nil
+}
+
+// elementalConductorStatus maps the status of the given job to a Status.
+// Archived jobs may have finished successfully or not, so their status is
+// inferred from the error messages and completion time, and is unknown when
+// neither is available.
+func elementalConductorStatus(job *elementalconductor.Job) Status {
+	switch job.Status.Normalize() {
+	case elementalconductor.JobStatusPending:
+		return StatusQueued
+	case elementalconductor.JobStatusPreprocessing,
+		elementalconductor.JobStatusRunning,
+		elementalconductor.JobStatusPostprocessing:
+		return StatusStarted
+	case elementalconductor.JobStatusComplete:
+		return StatusFinished
+	case elementalconductor.JobStatusArchived:
+		if len(job.ErrorMessages) > 0 || !job.ErroredTime.IsZero() {
+			return StatusFailed
+		}
+		if !job.CompleteTime.IsZero() {
+			return StatusFinished
+		}
+	case elementalconductor.JobStatusError:
+		return StatusFailed
+	case elementalconductor.JobStatusCancelled:
+		return StatusCanceled
+	}
+	return StatusUnknown
+}
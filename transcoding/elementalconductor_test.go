@@ -0,0 +1,276 @@
+package transcoding
+
+import (
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/NYTimes/encoding-wrapper/elementalconductor"
+)
+
+var _ Provider = &ElementalConductorProvider{}
+
+type fakeConductorRequest struct {
+	req  *http.Request
+	body []byte
+}
+
+func startConductorServer(content string) (*httptest.Server, chan fakeConductorRequest) {
+	requests := make(chan fakeConductorRequest, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		requests <- fakeConductorRequest{req: r, body: data}
+		w.Header().Set("Content-Type", "application/xml")
+		w.Write([]byte(content))
+	}))
+	return server, requests
+}
+
+func newConductorClient(url string) *elementalconductor.Client {
+	return elementalconductor.NewClient(url, "myuser", "secret-key", 45, "aws-access-key", "aws-secret-key", "s3://default-bucket/")
+}
+
+func TestElementalConductorSubmit(t *testing.T) {
+	server, requests := startConductorServer(`<?xml version="1.0" encoding="UTF-8"?>
+<job href="/jobs/1">
+  <status>Pending</status>
+</job>`)
+	defer server.Close()
+	provider := NewElementalConductorProvider(newConductorClient(server.URL))
+	status, err := provider.Submit(&Job{
+		Source: "s3://mybucket/source.mov",
+		Outputs: []Output{
+			{Preset: "mp4_1080p", Destination: "s3://mybucket/1080p/", Container: "mp4"},
+			{Preset: "hls_720p", Container: "m3u8"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &JobStatus{
+		ProviderName:   ElementalConductorName,
+		ProviderJobID:  "1",
+		Status:         StatusQueued,
+		ProviderStatus: "Pending",
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("wrong status\nwant %#v\ngot  %#v", expected, status)
+	}
+	req := <-requests
+	if req.req.Method != "POST" || req.req.URL.Path != "/api/jobs" {
+		t.Errorf("wrong request: %s %s", req.req.Method, req.req.URL.Path)
+	}
+	var job elementalconductor.Job
+	err = xml.Unmarshal(req.body, &job)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentials := func(uri string) *elementalconductor.Location {
+		return &elementalconductor.Location{URI: uri, Username: "aws-access-key", Password: "aws-secret-key"}
+	}
+	expectedJob := elementalconductor.Job{
+		XMLName: xml.Name{Local: "job"},
+		Input: []elementalconductor.Input{
+			{FileInput: *credentials("s3://mybucket/source.mov")},
+		},
+		StreamAssembly: []elementalconductor.StreamAssembly{
+			{Name: "stream_1", Preset: "mp4_1080p"},
+			{Name: "stream_2", Preset: "hls_720p"},
+		},
+		OutputGroup: []elementalconductor.OutputGroup{
+			{
+				Order:             1,
+				Type:              elementalconductor.FileOutputGroupType,
+				FileGroupSettings: &elementalconductor.FileGroupSettings{Destination: credentials("s3://mybucket/1080p/")},
+				Output: []elementalconductor.Output{
+					{StreamAssemblyName: "stream_1", Order: 1, Container: elementalconductor.MPEG4},
+				},
+			},
+			{
+				Order:                  2,
+				Type:                   elementalconductor.AppleLiveOutputGroupType,
+				AppleLiveGroupSettings: &elementalconductor.AppleLiveGroupSettings{Destination: credentials("s3://default-bucket/")},
+				Output: []elementalconductor.Output{
+					{StreamAssemblyName: "stream_2", Order: 1, Container: elementalconductor.AppleHTTPLiveStreaming},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(job, expectedJob) {
+		t.Errorf("wrong job\nwant %#v\ngot  %#v", expectedJob, job)
+	}
+}
+
+func TestElementalConductorStatus(t *testing.T) {
+	server, requests := startConductorServer(`<?xml version="1.0" encoding="UTF-8"?>
+<job href="/jobs/1">
+  <status>Running</status>
+  <pct_complete>42</pct_complete>
+  <output_group>
+    <order>1</order>
+    <file_group_settings>
+      <destination>
+        <uri>s3://mybucket/1080p/</uri>
+      </destination>
+    </file_group_settings>
+    <type>file_group_settings</type>
+    <output>
+      <full_uri>s3://mybucket/1080p/source.mp4</full_uri>
+      <stream_assembly_name>stream_1</stream_assembly_name>
+      <container>mp4</container>
+    </output>
+  </output_group>
+  <stream_assembly>
+    <name>stream_1</name>
+    <video_description>
+      <codec>h.264</codec>
+      <width>1920</width>
+      <height>1080</height>
+      <h264_settings>
+        <bitrate>5000000</bitrate>
+      </h264_settings>
+    </video_description>
+  </stream_assembly>
+</job>`)
+	defer server.Close()
+	provider := NewElementalConductorProvider(newConductorClient(server.URL))
+	status, err := provider.Status("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &JobStatus{
+		ProviderName:   ElementalConductorName,
+		ProviderJobID:  "1",
+		Status:         StatusStarted,
+		ProviderStatus: "Running",
+		Progress:       42,
+		Outputs: []OutputFile{
+			{
+				Destination: "s3://mybucket/1080p/source.mp4",
+				Container:   "mp4",
+				VideoCodec:  "h.264",
+				Width:       1920,
+				Height:      1080,
+				Bitrate:     5000000,
+			},
+		},
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("wrong status\nwant %#v\ngot  %#v", expected, status)
+	}
+	req := <-requests
+	if req.req.URL.Path != "/api/jobs/1" {
+		t.Errorf("wrong path\nwant %q\ngot  %q", "/api/jobs/1", req.req.URL.Path)
+	}
+}
+
+func TestElementalConductorStatusMapping(t *testing.T) {
+	var tests = []struct {
+		input    elementalconductor.JobStatus
+		expected Status
+	}{
+		{elementalconductor.JobStatusPending, StatusQueued},
+		{elementalconductor.JobStatusPreprocessing, StatusStarted},
+		{elementalconductor.JobStatusRunning, StatusStarted},
+		{elementalconductor.JobStatusPostprocessing, StatusStarted},
+		{elementalconductor.JobStatusComplete, StatusFinished},
+		{elementalconductor.JobStatusArchived, StatusUnknown},
+		{elementalconductor.JobStatusError, StatusFailed},
+		{elementalconductor.JobStatusCancelled, StatusCanceled},
+		{"canceled", StatusCanceled},
+		{"whatever", StatusUnknown},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(string(test.input), func(t *testing.T) {
+			if got := elementalConductorStatus(&elementalconductor.Job{Status: test.input}); got != test.expected {
+				t.Errorf("wrong status\nwant %q\ngot  %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestElementalConductorArchivedStatus(t *testing.T) {
+	finishedAt := elementalconductor.DateTime{Time: time.Date(2016, 3, 1, 12, 0, 0, 0, time.UTC)}
+	var tests = []struct {
+		testCase string
+		job      elementalconductor.Job
+		expected Status
+	}{
+		{
+			"completed",
+			elementalconductor.Job{Status: elementalconductor.JobStatusArchived, CompleteTime: finishedAt},
+			StatusFinished,
+		},
+		{
+			"errored",
+			elementalconductor.Job{Status: elementalconductor.JobStatusArchived, ErroredTime: finishedAt},
+			StatusFailed,
+		},
+		{
+			"with error messages",
+			elementalconductor.Job{
+				Status:        elementalconductor.JobStatusArchived,
+				ErrorMessages: []elementalconductor.JobError{{Message: "failed to download input"}},
+			},
+			StatusFailed,
+		},
+		{
+			"cancelled",
+			elementalconductor.Job{Status: elementalconductor.JobStatusArchived},
+			StatusUnknown,
+		},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.testCase, func(t *testing.T) {
+			if got := elementalConductorStatus(&test.job); got != test.expected {
+				t.Errorf("wrong status\nwant %q\ngot  %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestElementalConductorCancel(t *testing.T) {
+	server, requests := startConductorServer(`<job href="/jobs/1"><status>Canceled</status></job>`)
+	defer server.Close()
+	provider := NewElementalConductorProvider(newConductorClient(server.URL))
+	err := provider.Cancel("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := <-requests
+	if req.req.Method != "POST" || req.req.URL.Path != "/api/jobs/1/cancel" {
+		t.Errorf("wrong request: %s %s", req.req.Method, req.req.URL.Path)
+	}
+}
+
+func TestElementalConductorListPresets(t *testing.T) {
+	server, _ := startConductorServer(`<?xml version="1.0" encoding="UTF-8"?>
+<preset_list>
+  <preset href="/presets/1">
+    <name>mp4_1080p</name>
+    <description>MP4 1080p</description>
+    <container>mp4</container>
+    <video_description>
+      <codec>h.264</codec>
+    </video_description>
+  </preset>
+</preset_list>`)
+	defer server.Close()
+	provider := NewElementalConductorProvider(newConductorClient(server.URL))
+	presets, err := provider.ListPresets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Preset{
+		{Name: "mp4_1080p", Description: "MP4 1080p", Container: "mp4", VideoCodec: "h.264"},
+	}
+	if !reflect.DeepEqual(presets, expected) {
+		t.Errorf("wrong presets\nwant %#v\ngot  %#v", expected, presets)
+	}
+}
@@ -0,0 +1,128 @@
+package transcoding
+
+import (
+	"errors"
+
+	"github.com/NYTimes/encoding-wrapper/encodingcom"
+)
+
+// EncodingComName is the value of ProviderName in JobStatus for jobs in the
+// Encoding.com provider.
+const EncodingComName = "encoding.com"
+
+// EncodingComProvider is the Provider implementation backed by an
+// encodingcom.Client.
+type EncodingComProvider struct {
+	Client *encodingcom.Client
+	Region string
+}
+
+// NewEncodingComProvider creates a Provider that uses the given client,
+// sending media to the given region.
+func NewEncodingComProvider(client *encodingcom.Client, region string) *EncodingComProvider {
+	return &EncodingComProvider{Client: client, Region: region}
+}
+
+// Submit adds a new media in Encoding.com, with one format for each output
+// in the job. Outputs without a destination are sent without one, so
+// Encoding.com stores them in its own storage.
+func (p *EncodingComProvider) Submit(job *Job) (*JobStatus, error) {
+	formats := make([]encodingcom.Format, len(job.Outputs))
+	for i, output := range job.Outputs {
+		formats[i] = encodingcom.Format{OutputPreset: output.Preset}
+		if output.Destination != "" {
+			formats[i].Destination = []string{output.Destination}
+		}
+		if output.Container != "" {
+			formats[i].Output = []string{output.Container}
+		}
+	}
+	resp, err := p.Client.AddMedia([]string{job.Source}, formats, p.Region)
+	if err != nil {
+		return nil, err
+	}
+	return &JobStatus{
+		ProviderName:   EncodingComName,
+		ProviderJobID:  resp.MediaID,
+		Status:         StatusQueued,
+		ProviderStatus: string(encodingcom.MediaStatusNew),
+	}, nil
+}
+
+// Status returns the status of the given media in Encoding.com, with one
+// output file for each format. The Destination of the output file is the
+// first destination reported for the format (Submit sends at most one), and
+// it's left empty when none is reported.
+func (p *EncodingComProvider) Status(jobID string) (*JobStatus, error) {
+	status, err := p.Client.GetStatus([]string{jobID}, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(status) == 0 {
+		return nil, errors.New("no status returned for media " + jobID)
+	}
+	mediaStatus := status[0]
+	jobStatus := JobStatus{
+		ProviderName:   EncodingComName,
+		ProviderJobID:  mediaStatus.MediaID,
+		Status:         encodingComStatus(mediaStatus.MediaStatus),
+		ProviderStatus: string(mediaStatus.MediaStatus),
+		Progress:       mediaStatus.Progress,
+	}
+	for _, format := range mediaStatus.Formats {
+		outputFile := OutputFile{
+			Container:  format.Output,
+			VideoCodec: format.VideoCodec,
+			Width:      format.GetWidth(),
+			Height:     format.GetHeight(),
+			Bitrate:    format.GetBitrate(),
+		}
+		if len(format.Destinations) > 0 {
+			outputFile.Destination = format.Destinations[0].Name
+		}
+		jobStatus.Outputs = append(jobStatus.Outputs, outputFile)
+	}
+	return &jobStatus, nil
+}
+
+// Cancel cancels the given media in Encoding.com.
+func (p *EncodingComProvider) Cancel(jobID string) error {
+	_, err := p.Client.CancelMedia(jobID)
+	return err
+}
+
+// ListPresets returns both user and UI presets from Encoding.com.
+func (p *EncodingComProvider) ListPresets() ([]Preset, error) {
+	resp, err := p.Client.ListPresets(encodingcom.AllPresets)
+	if err != nil {
+		return nil, err
+	}
+	var presets []Preset
+	for _, preset := range append(resp.UserPresets, resp.UIPresets...) {
+		presets = append(presets, Preset{
+			Name:       preset.Name,
+			Container:  preset.Output,
+			VideoCodec: preset.Format.VideoCodec,
+		})
+	}
+	return presets, nil
+}
+
+func encodingComStatus(status encodingcom.MediaStatus) Status {
+	switch status {
+	case encodingcom.MediaStatusNew,
+		encodingcom.MediaStatusDownloading,
+		encodingcom.MediaStatusReadyToProcess,
+		encodingcom.MediaStatusWaitingForEncoder:
+		return StatusQueued
+	case encodingcom.MediaStatusProcessing, encodingcom.MediaStatusSaving:
+		return StatusStarted
+	case encodingcom.MediaStatusFinished:
+		return StatusFinished
+	case encodingcom.MediaStatusError:
+		return StatusFailed
+	case encodingcom.MediaStatusDeleted:
+		return StatusCanceled
+	}
+	return StatusUnknown
+}
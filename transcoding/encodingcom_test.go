@@ -0,0 +1,237 @@
+package transcoding
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/NYTimes/encoding-wrapper/encodingcom"
+)
+
+var _ Provider = &EncodingComProvider{}
+
+func startEncodingComServer(content string) (*httptest.Server, chan map[string]interface{}) {
+	requests := make(chan map[string]interface{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var m map[string]map[string]interface{}
+		json.Unmarshal([]byte(r.FormValue("json")), &m)
+		requests <- m["query"]
+		w.Write([]byte(content))
+	}))
+	return server, requests
+}
+
+func TestEncodingComSubmit(t *testing.T) {
+	server, requests := startEncodingComServer(`{"response": {"message": "Added", "MediaID": "1234567"}}`)
+	defer server.Close()
+	client, _ := encodingcom.NewClient(server.URL, "myuser", "123")
+	provider := NewEncodingComProvider(client, "us-east-1")
+	status, err := provider.Submit(&Job{
+		Source: "http://some.video/file.mov",
+		Outputs: []Output{
+			{Preset: "mp4_1080p", Destination: "s3://mybucket/file_1080p.mp4"},
+			{Preset: "my_hls", Destination: "s3://mybucket/hls/", Container: "advanced_hls"},
+			{Preset: "webm_720p"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &JobStatus{
+		ProviderName:   EncodingComName,
+		ProviderJobID:  "1234567",
+		Status:         StatusQueued,
+		ProviderStatus: "New",
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("wrong status\nwant %#v\ngot  %#v", expected, status)
+	}
+	req := <-requests
+	expectedFormats := []interface{}{
+		map[string]interface{}{
+			"output_preset":          "mp4_1080p",
+			"destination":            []interface{}{"s3://mybucket/file_1080p.mp4"},
+			"video_codec_parameters": map[string]interface{}{},
+		},
+		map[string]interface{}{
+			"output":                 []interface{}{"advanced_hls"},
+			"output_preset":          "my_hls",
+			"destination":            []interface{}{"s3://mybucket/hls/"},
+			"video_codec_parameters": map[string]interface{}{},
+		},
+		map[string]interface{}{
+			"output_preset":          "webm_720p",
+			"video_codec_parameters": map[string]interface{}{},
+		},
+	}
+	if !reflect.DeepEqual(req["format"], expectedFormats) {
+		t.Errorf("wrong formats\nwant %#v\ngot  %#v", expectedFormats, req["format"])
+	}
+	if region := req["region"]; region != "us-east-1" {
+		t.Errorf("wrong region\nwant %q\ngot  %q", "us-east-1", region)
+	}
+}
+
+func TestEncodingComStatus(t *testing.T) {
+	server, _ := startEncodingComServer(`
+{
+	"response": {
+		"job": {
+			"id": "abc123",
+			"status": "Saving",
+			"progress": "87.5",
+			"format": {
+				"id": "f123",
+				"status": "Saving",
+				"output": "mp4",
+				"size": "1920x1080",
+				"bitrate": "3500k",
+				"video_codec": "libx264",
+				"destination": "s3://mybucket/file_1080p.mp4",
+				"destination_status": "Saving"
+			}
+		}
+	}
+}`)
+	defer server.Close()
+	client, _ := encodingcom.NewClient(server.URL, "myuser", "123")
+	provider := NewEncodingComProvider(client, "us-east-1")
+	status, err := provider.Status("abc123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := &JobStatus{
+		ProviderName:   EncodingComName,
+		ProviderJobID:  "abc123",
+		Status:         StatusStarted,
+		ProviderStatus: "Saving",
+		Progress:       87.5,
+		Outputs: []OutputFile{
+			{
+				Destination: "s3://mybucket/file_1080p.mp4",
+				Container:   "mp4",
+				VideoCodec:  "libx264",
+				Width:       1920,
+				Height:      1080,
+				Bitrate:     3500000,
+			},
+		},
+	}
+	if !reflect.DeepEqual(status, expected) {
+		t.Errorf("wrong status\nwant %#v\ngot  %#v", expected, status)
+	}
+}
+
+func TestEncodingComStatusNoDestination(t *testing.T) {
+	server, _ := startEncodingComServer(`
+{
+	"response": {
+		"job": {
+			"id": "abc123",
+			"status": "Finished",
+			"progress": "100",
+			"format": [
+				{
+					"id": "f123",
+					"status": "Finished",
+					"output": "webm",
+					"size": "1280x720",
+					"video_codec": "libvpx"
+				},
+				{
+					"id": "f124",
+					"status": "Finished",
+					"output": "mp4",
+					"destination": "s3://mybucket/file_1080p.mp4",
+					"destination_status": "Saved"
+				}
+			]
+		}
+	}
+}`)
+	defer server.Close()
+	client, _ := encodingcom.NewClient(server.URL, "myuser", "123")
+	provider := NewEncodingComProvider(client, "us-east-1")
+	status, err := provider.Status("abc123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []OutputFile{
+		{Container: "webm", VideoCodec: "libvpx", Width: 1280, Height: 720},
+		{Destination: "s3://mybucket/file_1080p.mp4", Container: "mp4"},
+	}
+	if !reflect.DeepEqual(status.Outputs, expected) {
+		t.Errorf("wrong outputs\nwant %#v\ngot  %#v", expected, status.Outputs)
+	}
+}
+
+func TestEncodingComStatusMapping(t *testing.T) {
+	var tests = []struct {
+		input    encodingcom.MediaStatus
+		expected Status
+	}{
+		{encodingcom.MediaStatusNew, StatusQueued},
+		{encodingcom.MediaStatusDownloading, StatusQueued},
+		{encodingcom.MediaStatusReadyToProcess, StatusQueued},
+		{encodingcom.MediaStatusWaitingForEncoder, StatusQueued},
+		{encodingcom.MediaStatusProcessing, StatusStarted},
+		{encodingcom.MediaStatusSaving, StatusStarted},
+		{encodingcom.MediaStatusFinished, StatusFinished},
+		{encodingcom.MediaStatusError, StatusFailed},
+		{encodingcom.MediaStatusDeleted, StatusCanceled},
+		{encodingcom.MediaStatus("Something new"), StatusUnknown},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(string(test.input), func(t *testing.T) {
+			if got := encodingComStatus(test.input); got != test.expected {
+				t.Errorf("wrong status\nwant %q\ngot  %q", test.expected, got)
+			}
+		})
+	}
+}
+
+func TestEncodingComCancel(t *testing.T) {
+	server, requests := startEncodingComServer(`{"response": {"message": "Deleted"}}`)
+	defer server.Close()
+	client, _ := encodingcom.NewClient(server.URL, "myuser", "123")
+	provider := NewEncodingComProvider(client, "us-east-1")
+	err := provider.Cancel("abc123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := <-requests
+	if req["action"] != "CancelMedia" || req["mediaid"] != "abc123" {
+		t.Errorf("wrong request: %#v", req)
+	}
+}
+
+func TestEncodingComListPresets(t *testing.T) {
+	server, _ := startEncodingComServer(`
+{
+	"response": {
+		"user": [
+			{"name": "my_hls", "type": "user", "output": "advanced_hls", "format": {"output": "advanced_hls"}}
+		],
+		"ui": [
+			{"name": "mp4_1080p", "type": "ui", "output": "mp4", "format": {"output": "mp4", "video_codec": "libx264"}}
+		]
+	}
+}`)
+	defer server.Close()
+	client, _ := encodingcom.NewClient(server.URL, "myuser", "123")
+	provider := NewEncodingComProvider(client, "us-east-1")
+	presets, err := provider.ListPresets()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []Preset{
+		{Name: "my_hls", Container: "advanced_hls"},
+		{Name: "mp4_1080p", Container: "mp4", VideoCodec: "libx264"},
+	}
+	if !reflect.DeepEqual(presets, expected) {
+		t.Errorf("wrong presets\nwant %#v\ngot  %#v", expected, presets)
+	}
+}
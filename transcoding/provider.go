@@ -0,0 +1,110 @@
+// Package transcoding provides a provider-agnostic interface for submitting
+// and tracking transcoding jobs, with adapters for the clients in the
+// encodingcom and elementalconductor packages.
+package transcoding // import "github.com/NYTimes/encoding-wrapper/transcoding"
+
+// Provider is the common interface implemented by the transcoding providers
+// supported by this package.
+type Provider interface {
+	// Submit sends the given job to the provider, returning its initial
+	// status.
+	Submit(job *Job) (*JobStatus, error)
+
+	// Status returns the current status of the job with the given id.
+	Status(jobID string) (*JobStatus, error)
+
+	// Cancel cancels the job with the given id.
+	Cancel(jobID string) error
+
+	// ListPresets returns the presets available in the provider.
+	ListPresets() ([]Preset, error)
+}
+
+// Status is the normalized status of a job, regardless of the provider.
+type Status string
+
+const (
+	// StatusQueued is the status of jobs waiting to be processed.
+	StatusQueued = Status("queued")
+
+	// StatusStarted is the status of jobs being processed.
+	StatusStarted = Status("started")
+
+	// StatusFinished is the status of jobs that were successfully
+	// processed.
+	StatusFinished = Status("finished")
+
+	// StatusFailed is the status of jobs that failed.
+	StatusFailed = Status("failed")
+
+	// StatusCanceled is the status of jobs that were canceled.
+	StatusCanceled = Status("canceled")
+
+	// StatusUnknown is the status of jobs whose status in the provider
+	// couldn't be mapped to any of the statuses above.
+	StatusUnknown = Status("unknown")
+)
+
+// IsTerminal returns whether the status represents a job that won't make any
+// progress anymore (finished, failed or canceled).
+func (s Status) IsTerminal() bool {
+	switch s {
+	case StatusFinished, StatusFailed, StatusCanceled:
+		return true
+	}
+	return false
+}
+
+// Job is the provider-agnostic representation of a job to be submitted.
+type Job struct {
+	// Source is the URL of the media to be transcoded.
+	Source string
+
+	// Outputs is the list of outputs that should be generated from the
+	// source.
+	Outputs []Output
+}
+
+// Output describes an output of a job: the preset used for generating it and
+// where it should be saved.
+//
+// Container is optional, when it's empty the container defined in the
+// preset is used. Destination is optional too, when it's empty the output is
+// saved to the default location of the provider.
+type Output struct {
+	Preset      string
+	Destination string
+	Container   string
+}
+
+// JobStatus is the normalized status of a job in a provider.
+//
+// Progress is a value between 0 and 100. ProviderStatus holds the status as
+// returned by the provider, for debugging purposes.
+type JobStatus struct {
+	ProviderName   string
+	ProviderJobID  string
+	Status         Status
+	ProviderStatus string
+	Progress       float64
+	Outputs        []OutputFile
+}
+
+// OutputFile describes a file generated (or being generated) by a job.
+// Fields that aren't reported by the provider are left empty.
+type OutputFile struct {
+	Destination string
+	Container   string
+	VideoCodec  string
+	Width       int64
+	Height      int64
+	Bitrate     int64
+}
+
+// Preset is the provider-agnostic representation of a preset.
+type Preset struct {
+	Name        string
+	Description string
+	Container   string
+	VideoCodec  string
+}